@@ -1,9 +1,12 @@
 package main
 
 import (
+	"log"
+
 	"github.com/Satishcg12/CentralAuthV3/server/internal"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/middlewares"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/ratelimit"
 )
 
 func main() {
@@ -12,9 +15,17 @@ func main() {
 
 	// Initialize server
 	srv := internal.InitializeServer(cfg)
+
+	// Set up the rate limit backend
+	limiter, err := ratelimit.New(cfg.RateLimit, srv.Store)
+	if err != nil {
+		log.Fatalf("Failed to set up rate limiting: %v", err)
+	}
+
 	cm := middlewares.NewMiddleware(middlewares.Middleware{
-		Store:  srv.Store,
-		Config: cfg,
+		Store:   srv.Store,
+		Config:  cfg,
+		Limiter: limiter,
 	})
 
 	// Set up global middleware
@@ -2,11 +2,16 @@ package config
 
 import (
 	"log"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/mailer"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/ratelimit"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/webauthn"
 	"github.com/joho/godotenv"
 )
 
@@ -16,20 +21,86 @@ type Config struct {
 	ReadTimeout    time.Duration
 	WriteTimeout   time.Duration
 	ShutdownPeriod time.Duration
-	ClientURL      string // URL of the client application for CORS
+	ClientURL      string   // URL of the client application for CORS
+	TrustedProxies []string // CIDR ranges of the reverse proxies whose X-Forwarded-For header is trusted
 	DB             db.Config
+	Mail           mailer.Config
 	JWT            JWTConfig
+	OAuth          OAuthConfig
+	Auth           AuthConfig
+	PasswordPolicy PasswordPolicyConfig
+	WebAuthn       webauthn.Config
+	RateLimit      ratelimit.Config
 	AdminEmail     string // Email address that automatically gets admin role and permissions
 }
 
 // JWTConfig holds JWT related configuration
 type JWTConfig struct {
-	Secret             string
+	Issuer             string        // Public base URL of this server, used as the iss claim
+	SigningAlgorithm   string        // RS256 or ES256, used for newly generated keys
+	KeyEncryptionKey   string        // Secret the private signing keys are encrypted with at rest
+	KeyRotationPeriod  time.Duration // How long a signing key stays active before the next one takes over
+	KeyPrePublishAhead time.Duration // How long a new key is published in the JWKS before it activates
 	ExpiryHours        int
 	RefreshSecret      string
 	RefreshExpiryHours int // Changed from RefreshHours to RefreshExpiryHours for consistency
 }
 
+// OAuthConfig holds OAuth 2.0 authorization server configuration
+type OAuthConfig struct {
+	AuthorizationCodeExpiry time.Duration // How long an issued authorization code can be exchanged
+	LoginURL                string        // Login page unauthenticated authorization requests are sent to
+	ConsentURL              string        // Consent page users are sent to before authorizing a third-party client
+	DeviceCodeExpiry        time.Duration // How long a device authorization request can be approved
+	DevicePollInterval      time.Duration // Minimum time devices must wait between token requests
+	DeviceVerificationURL   string        // Page users enter the user code of a device authorization request on
+	RegistrationToken       string        // Initial access token required for dynamic client registration, disabled when empty
+	LogoutURL               string        // Page users are sent to after logging out when the client did not ask to get them back
+	LogoutConfirmURL        string        // Page users confirm a logout request on that did not come with an ID token
+}
+
+// PasswordPolicyConfig holds the rules new passwords have to follow
+type PasswordPolicyConfig struct {
+	MinLength             int    // Fewest characters a password can have
+	MaxLength             int    // Most characters a password can have
+	MinCharacterClasses   int    // How many of lowercase letters, uppercase letters, digits and symbols a password has to mix
+	DisallowPersonalInfo  bool   // Whether a password cannot contain the email address or name of the user
+	History               int    // Latest passwords of a user that cannot be reused, 0 disables the history
+	BreachedPasswordsFile string // File of sorted SHA-1 hashes of breached passwords that are refused, the check is disabled when empty
+}
+
+// Email verification policies, deciding what users with an unverified email address can do
+const (
+	EmailVerificationOptional   = "optional"   // Nothing is restricted
+	EmailVerificationRequired   = "required"   // Login is refused until the email is verified
+	EmailVerificationRestricted = "restricted" // Clients can only be granted UnverifiedEmailScopes
+)
+
+// AuthConfig holds user account configuration
+type AuthConfig struct {
+	EmailVerificationURL            string        // Page the link in verification emails points to, the token is added to its query
+	EmailVerificationExpiry         time.Duration // How long a verification link can be used
+	EmailVerificationResendInterval time.Duration // Minimum time between verification emails to the same user
+	EmailVerificationPolicy         string        // One of the EmailVerification policies
+	UnverifiedEmailScopes           []string      // Scopes clients can be granted for unverified users under the restricted policy
+	PasswordResetURL                string        // Page the link in password reset emails points to, the token is added to its query
+	PasswordResetExpiry             time.Duration // How long a password reset link can be used
+	PasswordResetRequestInterval    time.Duration // Minimum time between password reset emails to the same user
+	MFAIssuer                       string        // Name authenticator apps show for TOTP accounts
+	MFAChallengeExpiry              time.Duration // How long the second login step can be completed after the password was checked
+	MFAChallengeMaxAttempts         int           // Wrong codes allowed before the second login step has to be started over
+	AdminMFARequired                bool          // Whether admin endpoints require a login with a second factor
+	LoginMaxFailures                int           // Failed logins of an account before it is locked
+	LoginIPMaxFailures              int           // Failed logins from an IP address before it is locked out
+	LoginFailureWindow              time.Duration // How long a failed login counts towards a lockout
+	LoginLockoutDuration            time.Duration // How long logins are refused once locked
+	LoginDelayBase                  time.Duration // Wait after a failed login of an account, doubling with every further failure
+	LoginDelayMax                   time.Duration // Longest wait between failed logins of an account
+	LoginUnlockByEmail              bool          // Whether users are emailed a link to unlock their account when it is locked
+	AccountUnlockURL                string        // Page the link in account locked emails points to, the token is added to its query
+	AccountUnlockExpiry             time.Duration // How long an unlock link can be used
+}
+
 // NewConfig creates a new configuration with default values or from environment variables
 func NewConfig() *Config {
 	// Load .env file if it exists
@@ -55,12 +126,69 @@ func NewConfig() *Config {
 			DBName:   "centralauth",
 			SSLMode:  "disable",
 		},
+		Mail: mailer.Config{
+			Driver:    mailer.DriverOutbox,
+			From:      "no-reply@localhost",
+			FromName:  "CentralAuth",
+			Port:      587,
+			OutboxDir: "tmp/outbox",
+		},
 		JWT: JWTConfig{
-			Secret:             "your-secret-key-change-in-production",
-			ExpiryHours:        24, // 1 day
+			Issuer:             "http://localhost:8080",
+			SigningAlgorithm:   "RS256",
+			KeyEncryptionKey:   "your-key-encryption-key-change-in-production",
+			KeyRotationPeriod:  30 * 24 * time.Hour, // 30 days
+			KeyPrePublishAhead: 24 * time.Hour,      // 1 day
+			ExpiryHours:        24,                  // 1 day
 			RefreshSecret:      "your-refresh-secret-key-change-in-production",
 			RefreshExpiryHours: 168, // 7 days
 		},
+		OAuth: OAuthConfig{
+			AuthorizationCodeExpiry: 60 * time.Second,
+			DeviceCodeExpiry:        10 * time.Minute,
+			DevicePollInterval:      5 * time.Second,
+		},
+		Auth: AuthConfig{
+			EmailVerificationExpiry:         24 * time.Hour,
+			EmailVerificationResendInterval: 60 * time.Second,
+			EmailVerificationPolicy:         EmailVerificationOptional,
+			UnverifiedEmailScopes:           []string{"openid", "profile"},
+			PasswordResetExpiry:             time.Hour,
+			PasswordResetRequestInterval:    60 * time.Second,
+			MFAIssuer:                       "CentralAuth",
+			MFAChallengeExpiry:              5 * time.Minute,
+			MFAChallengeMaxAttempts:         5,
+			AdminMFARequired:                true,
+			LoginMaxFailures:                10,
+			LoginIPMaxFailures:              100,
+			LoginFailureWindow:              15 * time.Minute,
+			LoginLockoutDuration:            15 * time.Minute,
+			LoginDelayBase:                  time.Second,
+			LoginDelayMax:                   30 * time.Second,
+			LoginUnlockByEmail:              true,
+			AccountUnlockExpiry:             time.Hour,
+		},
+		PasswordPolicy: PasswordPolicyConfig{
+			MinLength:            8,
+			MaxLength:            128,
+			MinCharacterClasses:  2,
+			DisallowPersonalInfo: true,
+			History:              5,
+		},
+		WebAuthn: webauthn.Config{
+			RPName:  "CentralAuth",
+			Timeout: 5 * time.Minute,
+		},
+		RateLimit: ratelimit.Config{
+			Enabled:  true,
+			Backend:  ratelimit.BackendMemory,
+			API:      ratelimit.Limit{Requests: 300, Period: time.Minute, Key: ratelimit.KeyUser},
+			Login:    ratelimit.Limit{Requests: 20, Period: time.Minute, Key: ratelimit.KeyIP},
+			Register: ratelimit.Limit{Requests: 10, Period: time.Hour, Burst: 5, Key: ratelimit.KeyIP},
+			Token:    ratelimit.Limit{Requests: 60, Period: time.Minute, Key: ratelimit.KeyIP},
+			// User codes are short enough to guess, they are entered a few times at most
+			DeviceVerification: ratelimit.Limit{Requests: 10, Period: time.Minute, Key: ratelimit.KeyIP},
+		},
 	}
 
 	// Override with environment variables if present
@@ -84,6 +212,10 @@ func NewConfig() *Config {
 		config.ClientURL = clientURL
 	}
 
+	if trustedProxies := os.Getenv("TRUSTED_PROXIES"); trustedProxies != "" {
+		config.TrustedProxies = strings.Fields(trustedProxies)
+	}
+
 	// Admin email from environment
 	if adminEmail := os.Getenv("ADMIN_EMAIL"); adminEmail != "" {
 		config.AdminEmail = adminEmail
@@ -114,9 +246,58 @@ func NewConfig() *Config {
 		config.DB.SSLMode = dbSSLMode
 	}
 
+	// Mail config from environment
+	if mailDriver := os.Getenv("MAIL_DRIVER"); mailDriver != "" {
+		config.Mail.Driver = mailDriver
+	}
+
+	if mailFrom := os.Getenv("MAIL_FROM"); mailFrom != "" {
+		config.Mail.From = mailFrom
+	}
+
+	if mailFromName := os.Getenv("MAIL_FROM_NAME"); mailFromName != "" {
+		config.Mail.FromName = mailFromName
+	}
+
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		config.Mail.Host = smtpHost
+	}
+
+	if smtpPort := getEnvAsInt("SMTP_PORT", 587); smtpPort != 0 {
+		config.Mail.Port = smtpPort
+	}
+
+	if smtpUsername := os.Getenv("SMTP_USERNAME"); smtpUsername != "" {
+		config.Mail.Username = smtpUsername
+	}
+
+	if smtpPassword := os.Getenv("SMTP_PASSWORD"); smtpPassword != "" {
+		config.Mail.Password = smtpPassword
+	}
+
+	if outboxDir := os.Getenv("MAIL_OUTBOX_DIR"); outboxDir != "" {
+		config.Mail.OutboxDir = outboxDir
+	}
+
 	// JWT config from environment
-	if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
-		config.JWT.Secret = jwtSecret
+	if jwtIssuer := os.Getenv("JWT_ISSUER"); jwtIssuer != "" {
+		config.JWT.Issuer = strings.TrimSuffix(jwtIssuer, "/")
+	}
+
+	if signingAlgorithm := os.Getenv("JWT_SIGNING_ALGORITHM"); signingAlgorithm != "" {
+		config.JWT.SigningAlgorithm = signingAlgorithm
+	}
+
+	if keyEncryptionKey := os.Getenv("JWT_KEY_ENCRYPTION_KEY"); keyEncryptionKey != "" {
+		config.JWT.KeyEncryptionKey = keyEncryptionKey
+	}
+
+	if rotationPeriod := getEnvAsDuration("JWT_KEY_ROTATION_PERIOD", 30*24*time.Hour); rotationPeriod != 0 {
+		config.JWT.KeyRotationPeriod = rotationPeriod
+	}
+
+	if prePublish := getEnvAsDuration("JWT_KEY_PREPUBLISH", 24*time.Hour); prePublish != 0 {
+		config.JWT.KeyPrePublishAhead = prePublish
 	}
 
 	if jwtExpiry := getEnvAsInt("JWT_EXPIRY_HOURS", 24); jwtExpiry != 0 {
@@ -131,9 +312,226 @@ func NewConfig() *Config {
 		config.JWT.RefreshExpiryHours = jwtRefreshHours // Changed from RefreshHours to RefreshExpiryHours
 	}
 
+	// OAuth config from environment
+	if codeExpiry := getEnvAsDuration("OAUTH_CODE_EXPIRY", 60*time.Second); codeExpiry != 0 {
+		config.OAuth.AuthorizationCodeExpiry = codeExpiry
+	}
+
+	if loginURL := os.Getenv("OAUTH_LOGIN_URL"); loginURL != "" {
+		config.OAuth.LoginURL = loginURL
+	} else {
+		config.OAuth.LoginURL = config.ClientURL + "/login"
+	}
+
+	if consentURL := os.Getenv("OAUTH_CONSENT_URL"); consentURL != "" {
+		config.OAuth.ConsentURL = consentURL
+	} else {
+		config.OAuth.ConsentURL = config.ClientURL + "/consent"
+	}
+
+	if deviceCodeExpiry := getEnvAsDuration("OAUTH_DEVICE_CODE_EXPIRY", 10*time.Minute); deviceCodeExpiry != 0 {
+		config.OAuth.DeviceCodeExpiry = deviceCodeExpiry
+	}
+
+	if pollInterval := getEnvAsDuration("OAUTH_DEVICE_POLL_INTERVAL", 5*time.Second); pollInterval != 0 {
+		config.OAuth.DevicePollInterval = pollInterval
+	}
+
+	if verificationURL := os.Getenv("OAUTH_DEVICE_VERIFICATION_URL"); verificationURL != "" {
+		config.OAuth.DeviceVerificationURL = verificationURL
+	} else {
+		config.OAuth.DeviceVerificationURL = config.ClientURL + "/device"
+	}
+
+	if registrationToken := os.Getenv("OAUTH_REGISTRATION_TOKEN"); registrationToken != "" {
+		config.OAuth.RegistrationToken = registrationToken
+	}
+
+	if logoutURL := os.Getenv("OAUTH_LOGOUT_URL"); logoutURL != "" {
+		config.OAuth.LogoutURL = logoutURL
+	} else {
+		config.OAuth.LogoutURL = config.ClientURL + "/login"
+	}
+
+	if logoutConfirmURL := os.Getenv("OAUTH_LOGOUT_CONFIRM_URL"); logoutConfirmURL != "" {
+		config.OAuth.LogoutConfirmURL = logoutConfirmURL
+	} else {
+		config.OAuth.LogoutConfirmURL = config.ClientURL + "/logout"
+	}
+
+	// Account config from environment
+	if verificationURL := os.Getenv("EMAIL_VERIFICATION_URL"); verificationURL != "" {
+		config.Auth.EmailVerificationURL = verificationURL
+	} else {
+		config.Auth.EmailVerificationURL = config.ClientURL + "/verify-email"
+	}
+
+	if verificationExpiry := getEnvAsDuration("EMAIL_VERIFICATION_EXPIRY", 24*time.Hour); verificationExpiry != 0 {
+		config.Auth.EmailVerificationExpiry = verificationExpiry
+	}
+
+	if resendInterval := getEnvAsDuration("EMAIL_VERIFICATION_RESEND_INTERVAL", 60*time.Second); resendInterval != 0 {
+		config.Auth.EmailVerificationResendInterval = resendInterval
+	}
+
+	switch policy := os.Getenv("EMAIL_VERIFICATION_POLICY"); policy {
+	case EmailVerificationOptional, EmailVerificationRequired, EmailVerificationRestricted:
+		config.Auth.EmailVerificationPolicy = policy
+	case "":
+	default:
+		log.Printf("Unknown EMAIL_VERIFICATION_POLICY %q, using %q", policy, config.Auth.EmailVerificationPolicy)
+	}
+
+	if unverifiedScopes := os.Getenv("UNVERIFIED_EMAIL_SCOPES"); unverifiedScopes != "" {
+		config.Auth.UnverifiedEmailScopes = strings.Fields(unverifiedScopes)
+	}
+
+	if resetURL := os.Getenv("PASSWORD_RESET_URL"); resetURL != "" {
+		config.Auth.PasswordResetURL = resetURL
+	} else {
+		config.Auth.PasswordResetURL = config.ClientURL + "/reset-password"
+	}
+
+	if resetExpiry := getEnvAsDuration("PASSWORD_RESET_EXPIRY", time.Hour); resetExpiry != 0 {
+		config.Auth.PasswordResetExpiry = resetExpiry
+	}
+
+	if resetInterval := getEnvAsDuration("PASSWORD_RESET_REQUEST_INTERVAL", 60*time.Second); resetInterval != 0 {
+		config.Auth.PasswordResetRequestInterval = resetInterval
+	}
+
+	if mfaIssuer := os.Getenv("MFA_ISSUER"); mfaIssuer != "" {
+		config.Auth.MFAIssuer = mfaIssuer
+	}
+
+	if challengeExpiry := getEnvAsDuration("MFA_CHALLENGE_EXPIRY", 5*time.Minute); challengeExpiry != 0 {
+		config.Auth.MFAChallengeExpiry = challengeExpiry
+	}
+
+	if maxAttempts := getEnvAsInt("MFA_CHALLENGE_MAX_ATTEMPTS", 5); maxAttempts != 0 {
+		config.Auth.MFAChallengeMaxAttempts = maxAttempts
+	}
+
+	if adminMFARequired, err := strconv.ParseBool(os.Getenv("ADMIN_MFA_REQUIRED")); err == nil {
+		config.Auth.AdminMFARequired = adminMFARequired
+	}
+
+	if maxFailures := getEnvAsInt("LOGIN_MAX_FAILURES", 10); maxFailures != 0 {
+		config.Auth.LoginMaxFailures = maxFailures
+	}
+
+	if ipMaxFailures := getEnvAsInt("LOGIN_IP_MAX_FAILURES", 100); ipMaxFailures != 0 {
+		config.Auth.LoginIPMaxFailures = ipMaxFailures
+	}
+
+	if failureWindow := getEnvAsDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute); failureWindow != 0 {
+		config.Auth.LoginFailureWindow = failureWindow
+	}
+
+	if lockoutDuration := getEnvAsDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute); lockoutDuration != 0 {
+		config.Auth.LoginLockoutDuration = lockoutDuration
+	}
+
+	// Zero disables the delays between failed logins
+	config.Auth.LoginDelayBase = getEnvAsDuration("LOGIN_DELAY_BASE", time.Second)
+
+	if delayMax := getEnvAsDuration("LOGIN_DELAY_MAX", 30*time.Second); delayMax != 0 {
+		config.Auth.LoginDelayMax = delayMax
+	}
+
+	if unlockByEmail, err := strconv.ParseBool(os.Getenv("LOGIN_UNLOCK_BY_EMAIL")); err == nil {
+		config.Auth.LoginUnlockByEmail = unlockByEmail
+	}
+
+	if unlockURL := os.Getenv("ACCOUNT_UNLOCK_URL"); unlockURL != "" {
+		config.Auth.AccountUnlockURL = unlockURL
+	} else {
+		config.Auth.AccountUnlockURL = config.ClientURL + "/unlock-account"
+	}
+
+	if unlockExpiry := getEnvAsDuration("ACCOUNT_UNLOCK_EXPIRY", time.Hour); unlockExpiry != 0 {
+		config.Auth.AccountUnlockExpiry = unlockExpiry
+	}
+
+	// Password policy from environment
+	if minLength := getEnvAsInt("PASSWORD_MIN_LENGTH", 8); minLength != 0 {
+		config.PasswordPolicy.MinLength = minLength
+	}
+
+	if maxLength := getEnvAsInt("PASSWORD_MAX_LENGTH", 128); maxLength != 0 {
+		config.PasswordPolicy.MaxLength = maxLength
+	}
+
+	// Zero allows passwords of any characters
+	config.PasswordPolicy.MinCharacterClasses = getEnvAsInt("PASSWORD_MIN_CHARACTER_CLASSES", 2)
+
+	if disallowPersonalInfo, err := strconv.ParseBool(os.Getenv("PASSWORD_DISALLOW_PERSONAL_INFO")); err == nil {
+		config.PasswordPolicy.DisallowPersonalInfo = disallowPersonalInfo
+	}
+
+	// Zero disables the password history
+	config.PasswordPolicy.History = getEnvAsInt("PASSWORD_HISTORY", 5)
+
+	if breachedFile := os.Getenv("BREACHED_PASSWORDS_FILE"); breachedFile != "" {
+		config.PasswordPolicy.BreachedPasswordsFile = breachedFile
+	}
+
+	// WebAuthn config from environment, credentials are scoped to the client application by default
+	if rpID := os.Getenv("WEBAUTHN_RP_ID"); rpID != "" {
+		config.WebAuthn.RPID = rpID
+	} else if clientURL, err := url.Parse(config.ClientURL); err == nil {
+		config.WebAuthn.RPID = clientURL.Hostname()
+	}
+
+	if rpName := os.Getenv("WEBAUTHN_RP_NAME"); rpName != "" {
+		config.WebAuthn.RPName = rpName
+	}
+
+	if origins := os.Getenv("WEBAUTHN_ORIGINS"); origins != "" {
+		config.WebAuthn.Origins = strings.Fields(origins)
+	} else {
+		config.WebAuthn.Origins = []string{strings.TrimSuffix(config.ClientURL, "/")}
+	}
+
+	if webauthnTimeout := getEnvAsDuration("WEBAUTHN_TIMEOUT", 5*time.Minute); webauthnTimeout != 0 {
+		config.WebAuthn.Timeout = webauthnTimeout
+	}
+
+	// Rate limit config from environment
+	if rateLimitEnabled, err := strconv.ParseBool(os.Getenv("RATE_LIMIT_ENABLED")); err == nil {
+		config.RateLimit.Enabled = rateLimitEnabled
+	}
+
+	if rateLimitBackend := os.Getenv("RATE_LIMIT_BACKEND"); rateLimitBackend != "" {
+		config.RateLimit.Backend = rateLimitBackend
+	}
+
+	config.RateLimit.API = getEnvAsRateLimit("RATE_LIMIT_API", config.RateLimit.API)
+	config.RateLimit.Login = getEnvAsRateLimit("RATE_LIMIT_LOGIN", config.RateLimit.Login)
+	config.RateLimit.Register = getEnvAsRateLimit("RATE_LIMIT_REGISTER", config.RateLimit.Register)
+	config.RateLimit.Token = getEnvAsRateLimit("RATE_LIMIT_TOKEN", config.RateLimit.Token)
+	config.RateLimit.DeviceVerification = getEnvAsRateLimit("RATE_LIMIT_DEVICE_VERIFICATION", config.RateLimit.DeviceVerification)
+
 	return config
 }
 
+// getEnvAsRateLimit overrides a rate limit with the environment variables starting with prefix,
+// _REQUESTS, _PERIOD in seconds, _BURST and _KEY
+func getEnvAsRateLimit(prefix string, limit ratelimit.Limit) ratelimit.Limit {
+	limit.Requests = getEnvAsInt(prefix+"_REQUESTS", limit.Requests)
+	limit.Period = getEnvAsDuration(prefix+"_PERIOD", limit.Period)
+	limit.Burst = getEnvAsInt(prefix+"_BURST", limit.Burst)
+
+	switch key := os.Getenv(prefix + "_KEY"); key {
+	case ratelimit.KeyIP, ratelimit.KeyUser, ratelimit.KeyClient:
+		limit.Key = key
+	case "":
+	default:
+		log.Printf("Unknown %s_KEY %q, using %q", prefix, key, limit.Key)
+	}
+	return limit
+}
+
 // getEnvAsDuration tries to parse an environment variable as a duration
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value, exists := os.LookupEnv(key); exists {
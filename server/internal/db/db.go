@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -41,6 +42,24 @@ func (s *Store) GetDB() *sql.DB {
 	return s.db
 }
 
+// ExecTx runs fn with queries bound to a single transaction, which is committed when fn
+// succeeds and rolled back when it returns an error
+func (s *Store) ExecTx(ctx context.Context, fn func(*sqlc.Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(s.Queries.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // Connect establishes a database connection
 func Connect(config Config) (*sql.DB, error) {
 	dsn := fmt.Sprintf(
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE authorization_code
+    ADD COLUMN scope TEXT NOT NULL DEFAULT '',
+    ADD COLUMN code_challenge VARCHAR(128),
+    ADD COLUMN code_challenge_method VARCHAR(10);
+
+-- Remember which code a refresh token was issued from so a replayed code can revoke it
+ALTER TABLE refresh_token
+    ADD COLUMN authorization_code_id UUID REFERENCES authorization_code(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_refresh_token_authorization_code_id ON refresh_token(authorization_code_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refresh_token_authorization_code_id;
+
+ALTER TABLE refresh_token
+    DROP COLUMN IF EXISTS authorization_code_id;
+
+ALTER TABLE authorization_code
+    DROP COLUMN IF EXISTS code_challenge_method,
+    DROP COLUMN IF EXISTS code_challenge,
+    DROP COLUMN IF EXISTS scope;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every refresh token belongs to a family started by the grant that first issued it,
+-- rotated tokens stay in the table so that their reuse can be detected
+ALTER TABLE refresh_token
+    ADD COLUMN family_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    ADD COLUMN scope TEXT NOT NULL DEFAULT '',
+    ADD COLUMN rotated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_refresh_token_family_id ON refresh_token(family_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refresh_token_family_id;
+
+ALTER TABLE refresh_token
+    DROP COLUMN IF EXISTS rotated_at,
+    DROP COLUMN IF EXISTS scope,
+    DROP COLUMN IF EXISTS family_id;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Grant types a client may use at the token endpoint and the scopes it may be granted,
+-- an empty allowed_scopes list does not restrict user authorizations
+ALTER TABLE clients
+    ADD COLUMN grant_types TEXT[] NOT NULL DEFAULT '{authorization_code,refresh_token}',
+    ADD COLUMN allowed_scopes TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE clients
+    DROP COLUMN IF EXISTS allowed_scopes,
+    DROP COLUMN IF EXISTS grant_types;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- OpenID Connect needs the nonce of the authorization request and the time the user
+-- authenticated when issuing ID tokens
+ALTER TABLE authorization_code
+    ADD COLUMN nonce VARCHAR(512),
+    ADD COLUMN auth_time TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE refresh_token
+    ADD COLUMN auth_time TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refresh_token
+    DROP COLUMN IF EXISTS auth_time;
+
+ALTER TABLE authorization_code
+    DROP COLUMN IF EXISTS auth_time,
+    DROP COLUMN IF EXISTS nonce;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Token signing keys. A key is published in the JWKS from its creation, signs tokens from
+-- activates_at until a newer key activates and stays published until retires_at.
+CREATE TABLE signing_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kid VARCHAR(64) NOT NULL UNIQUE,
+    algorithm VARCHAR(10) NOT NULL,
+    private_key BYTEA NOT NULL, -- PKCS#8 PEM encrypted with AES-256-GCM
+    activates_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    retires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_signing_keys_retires_at ON signing_keys(retires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS signing_keys;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Registry of the scopes clients may request
+CREATE TABLE scopes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    requires_consent BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO scopes (name, description, requires_consent) VALUES
+    ('openid', 'Sign you in with your account', FALSE),
+    ('profile', 'View your name, date of birth and profile details', TRUE),
+    ('email', 'View your email address', TRUE);
+
+-- Scopes a user has granted to a client, so consent is only asked once
+CREATE TABLE user_consents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, client_id)
+);
+
+-- First-party clients are trusted and never ask for consent
+ALTER TABLE clients
+    ADD COLUMN is_first_party BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE clients
+    DROP COLUMN IF EXISTS is_first_party;
+
+DROP TABLE IF EXISTS user_consents;
+DROP TABLE IF EXISTS scopes;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Pending device authorization requests (RFC 8628). The device polls with the device code
+-- while the user approves the request by entering the user code on another device.
+CREATE TABLE device_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    device_code VARCHAR(255) NOT NULL UNIQUE,
+    user_code VARCHAR(16) NOT NULL UNIQUE,
+    scope TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    auth_time TIMESTAMP WITH TIME ZONE,
+    poll_interval INTEGER NOT NULL,
+    last_polled_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_device_codes_expires_at ON device_codes(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS device_codes;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Client metadata of dynamic client registration (RFC 7591). Only the hash of the
+-- registration access token is stored.
+ALTER TABLE clients
+    ADD COLUMN token_endpoint_auth_method VARCHAR(50) NOT NULL DEFAULT 'client_secret_basic',
+    ADD COLUMN logo_uri VARCHAR(255),
+    ADD COLUMN registration_access_token VARCHAR(255);
+
+UPDATE clients
+SET token_endpoint_auth_method = 'none'
+WHERE is_confidential = FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE clients
+    DROP COLUMN IF EXISTS registration_access_token,
+    DROP COLUMN IF EXISTS logo_uri,
+    DROP COLUMN IF EXISTS token_endpoint_auth_method;
+-- +goose StatementEnd
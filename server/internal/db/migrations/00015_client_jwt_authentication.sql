@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Public keys of clients authenticating with private_key_jwt (RFC 7523), registered
+-- either inline as a JWKS document or as a URL the JWKS is fetched from
+ALTER TABLE clients
+    ADD COLUMN jwks TEXT,
+    ADD COLUMN jwks_uri VARCHAR(255);
+
+-- Identifiers of client assertions already used, kept until the assertion expires
+CREATE TABLE client_assertion_jtis (
+    client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    jti VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (client_id, jti)
+);
+
+CREATE INDEX idx_client_assertion_jtis_expires_at ON client_assertion_jtis(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS client_assertion_jtis;
+
+ALTER TABLE clients
+    DROP COLUMN IF EXISTS jwks_uri,
+    DROP COLUMN IF EXISTS jwks;
+-- +goose StatementEnd
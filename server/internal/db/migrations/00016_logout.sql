@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Logout metadata of clients (OpenID Connect RP-Initiated Logout and Back-Channel Logout)
+ALTER TABLE clients
+    ADD COLUMN post_logout_redirect_uris TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN backchannel_logout_uri VARCHAR(255),
+    ADD COLUMN backchannel_logout_session_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The login session codes and refresh tokens were issued to, so that logging out of the
+-- session reaches every client it was used with
+ALTER TABLE authorization_code
+    ADD COLUMN session_id UUID REFERENCES sessions(id) ON DELETE SET NULL;
+
+ALTER TABLE refresh_token
+    ADD COLUMN session_id UUID REFERENCES sessions(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_authorization_code_session_id ON authorization_code(session_id);
+CREATE INDEX idx_refresh_token_session_id ON refresh_token(session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refresh_token_session_id;
+DROP INDEX IF EXISTS idx_authorization_code_session_id;
+
+ALTER TABLE refresh_token
+    DROP COLUMN IF EXISTS session_id;
+
+ALTER TABLE authorization_code
+    DROP COLUMN IF EXISTS session_id;
+
+ALTER TABLE clients
+    DROP COLUMN IF EXISTS backchannel_logout_session_required,
+    DROP COLUMN IF EXISTS backchannel_logout_uri,
+    DROP COLUMN IF EXISTS post_logout_redirect_uris;
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Authentication methods used to log in (RFC 8176), copied into the amr claim of issued tokens
+ALTER TABLE sessions
+ADD COLUMN amr TEXT[] NOT NULL DEFAULT '{pwd}';
+
+-- Device authorization requests remember the session that approved them like authorization codes
+ALTER TABLE device_codes
+ADD COLUMN session_id UUID REFERENCES sessions(id) ON DELETE SET NULL;
+
+-- TOTP authenticators (RFC 6238). The secret is encrypted at rest and the authenticator is only
+-- used for logging in once it was confirmed with a first code. last_used_step keeps a code from
+-- being used twice.
+CREATE TABLE user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret BYTEA NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Single-use recovery codes for when the authenticator is lost, only their hash is stored
+CREATE TABLE mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(255) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+-- Second login step of users with MFA, handed out after the password was checked
+CREATE TABLE mfa_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(255) NOT NULL UNIQUE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_mfa_challenges_user_id ON mfa_challenges(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mfa_challenges;
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
+
+ALTER TABLE device_codes
+DROP COLUMN IF EXISTS session_id;
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS amr;
+-- +goose StatementEnd
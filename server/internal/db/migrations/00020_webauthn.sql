@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- WebAuthn credentials (security keys and passkeys). public_key is the COSE key the credential
+-- signs with and sign_count the signature counter it reported last, a counter going backwards
+-- means the authenticator was cloned.
+CREATE TABLE webauthn_credentials (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    aaguid BYTEA,
+    name VARCHAR(255) NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+
+-- Challenges of running WebAuthn ceremonies, looked up by the hash of the challenge the client
+-- signed. user_id is empty for passkey logins, where the user is only known from the credential.
+CREATE TABLE webauthn_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    ceremony VARCHAR(20) NOT NULL,
+    challenge VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webauthn_challenges;
+DROP TABLE IF EXISTS webauthn_credentials;
+-- +goose StatementEnd
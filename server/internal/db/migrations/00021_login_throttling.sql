@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Failed logins per account (by email address) and per IP address. Failures older than the
+-- failure window no longer count, reaching the threshold refuses logins until locked_until.
+CREATE TABLE login_throttles (
+    scope VARCHAR(20) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (scope, subject)
+);
+
+CREATE INDEX idx_login_throttles_last_failed_at ON login_throttles(last_failed_at);
+
+-- Single-use tokens of the links in account locked emails, only the hash of the token is stored
+CREATE TABLE account_unlock_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_account_unlock_tokens_user_id ON account_unlock_tokens(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_unlock_tokens;
+DROP TABLE IF EXISTS login_throttles;
+-- +goose StatementEnd
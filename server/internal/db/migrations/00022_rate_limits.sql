@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Token buckets of the rate limiter, shared by all server instances. Buckets are refilled when
+-- they are taken from and can be deleted once they would be full again, after expires_at. The
+-- table is not written to the WAL, losing the buckets in a crash only resets the limits.
+CREATE UNLOGGED TABLE rate_limit_buckets (
+    key VARCHAR(512) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_expires_at ON rate_limit_buckets(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rate_limit_buckets;
+-- +goose StatementEnd
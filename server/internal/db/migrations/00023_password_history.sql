@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Hashes of the latest passwords of every user, so that changing a password cannot reuse them
+CREATE TABLE password_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_password_history_user_id_created_at ON password_history(user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS password_history;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Whether the authorization request named its redirect_uri, the token request then has to
+-- repeat it (RFC 6749 section 4.1.3). The access token issued for the code is revoked when the
+-- code is replayed.
+ALTER TABLE authorization_code
+    ADD COLUMN redirect_uri_sent BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN access_token_jti TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE authorization_code
+    DROP COLUMN IF EXISTS access_token_jti,
+    DROP COLUMN IF EXISTS redirect_uri_sent;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- name: CreateAccountUnlockToken :one
+INSERT INTO account_unlock_tokens (
+    user_id,
+    token,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetAccountUnlockTokenByToken :one
+SELECT *
+FROM account_unlock_tokens
+WHERE token = $1
+LIMIT 1;
+
+-- name: MarkAccountUnlockTokenUsed :execrows
+UPDATE account_unlock_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidateUserAccountUnlockTokens :exec
+UPDATE account_unlock_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND used_at IS NULL;
@@ -0,0 +1,30 @@
+-- name: CreateAuthorizationCode :one
+INSERT INTO authorization_code (
+    user_id,
+    client_id,
+    code,
+    redirect_uri,
+    scope,
+    code_challenge,
+    code_challenge_method,
+    nonce,
+    auth_time,
+    expires_at,
+    session_id,
+    redirect_uri_sent
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING *;
+
+-- name: GetAuthorizationCodeByCode :one
+SELECT *
+FROM authorization_code
+WHERE code = $1
+LIMIT 1;
+
+-- name: MarkAuthorizationCodeUsed :execrows
+-- Records the access token issued for the code, so that it can be revoked when the code is replayed
+UPDATE authorization_code
+SET is_used = TRUE,
+    access_token_jti = $2
+WHERE id = $1 AND is_used = FALSE;
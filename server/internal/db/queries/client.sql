@@ -9,40 +9,30 @@ INSERT INTO clients (
     is_active,
     is_confidential,
     created_by,
+    grant_types,
+    allowed_scopes,
+    is_first_party,
+    token_endpoint_auth_method,
+    logo_uri,
+    jwks,
+    jwks_uri,
+    post_logout_redirect_uris,
+    backchannel_logout_uri,
+    backchannel_logout_session_required,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 ) RETURNING *;
 
 -- name: GetAllClients :many
-SELECT 
-    id,
-    name,
-    description,
-    client_id,
-    redirect_uris,
-    website_url,
-    is_active,
-    is_confidential,
-    created_at,
-    updated_at
+SELECT *
 FROM clients
 WHERE is_active = true
 ORDER BY created_at DESC;
 
 -- name: GetClientById :one
-SELECT 
-    id,
-    name,
-    description,
-    client_id,
-    redirect_uris,
-    website_url,
-    is_active,
-    is_confidential,
-    created_at,
-    updated_at
+SELECT *
 FROM clients
 WHERE id = $1 AND is_active = true
 LIMIT 1;
@@ -61,6 +51,16 @@ SET
     redirect_uris = $4,
     website_url = $5,
     is_confidential = $6,
+    grant_types = $7,
+    allowed_scopes = $8,
+    is_first_party = $9,
+    token_endpoint_auth_method = $10,
+    logo_uri = $11,
+    jwks = $12,
+    jwks_uri = $13,
+    post_logout_redirect_uris = $14,
+    backchannel_logout_uri = $15,
+    backchannel_logout_session_required = $16,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND is_active = true
 RETURNING 
@@ -72,6 +72,16 @@ RETURNING
     website_url,
     is_active,
     is_confidential,
+    grant_types,
+    allowed_scopes,
+    is_first_party,
+    token_endpoint_auth_method,
+    logo_uri,
+    jwks,
+    jwks_uri,
+    post_logout_redirect_uris,
+    backchannel_logout_uri,
+    backchannel_logout_session_required,
     created_at,
     updated_at;
 
@@ -99,4 +109,11 @@ WHERE client_id = $1 AND is_active = true
 RETURNING *;
 
 -- name: CountClients :one
-SELECT COUNT(*) FROM clients WHERE is_active = true;
\ No newline at end of file
+SELECT COUNT(*) FROM clients WHERE is_active = true;
+
+-- name: SetClientRegistrationAccessToken :exec
+UPDATE clients
+SET 
+    registration_access_token = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND is_active = true;
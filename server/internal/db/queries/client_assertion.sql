@@ -0,0 +1,12 @@
+-- name: RecordClientAssertionJTI :execrows
+INSERT INTO client_assertion_jtis (
+    client_id,
+    jti,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (client_id, jti) DO NOTHING;
+
+-- name: DeleteExpiredClientAssertionJTIs :execrows
+DELETE FROM client_assertion_jtis
+WHERE expires_at < CURRENT_TIMESTAMP;
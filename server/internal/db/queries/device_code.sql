@@ -0,0 +1,47 @@
+-- name: CreateDeviceCode :one
+INSERT INTO device_codes (
+    client_id,
+    device_code,
+    user_code,
+    scope,
+    poll_interval,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetDeviceCodeByDeviceCode :one
+SELECT *
+FROM device_codes
+WHERE device_code = $1
+LIMIT 1;
+
+-- name: GetDeviceCodeByUserCode :one
+SELECT *
+FROM device_codes
+WHERE user_code = $1
+LIMIT 1;
+
+-- name: ApproveDeviceCode :execrows
+UPDATE device_codes
+SET status = 'approved', user_id = $2, auth_time = $3, session_id = $4
+WHERE id = $1 AND status = 'pending';
+
+-- name: DenyDeviceCode :execrows
+UPDATE device_codes
+SET status = 'denied'
+WHERE id = $1 AND status = 'pending';
+
+-- name: ConsumeDeviceCode :execrows
+UPDATE device_codes
+SET status = 'consumed'
+WHERE id = $1 AND status = 'approved';
+
+-- name: UpdateDeviceCodePoll :exec
+UPDATE device_codes
+SET last_polled_at = $2, poll_interval = $3
+WHERE id = $1;
+
+-- name: DeleteExpiredDeviceCodes :execrows
+DELETE FROM device_codes
+WHERE expires_at < CURRENT_TIMESTAMP;
@@ -0,0 +1,32 @@
+-- name: CreateEmailVerificationToken :one
+INSERT INTO email_verification_tokens (
+    user_id,
+    token,
+    email,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetEmailVerificationTokenByToken :one
+SELECT *
+FROM email_verification_tokens
+WHERE token = $1
+LIMIT 1;
+
+-- name: GetLatestEmailVerificationToken :one
+SELECT *
+FROM email_verification_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: MarkEmailVerificationTokenUsed :execrows
+UPDATE email_verification_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidateUserEmailVerificationTokens :exec
+UPDATE email_verification_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND used_at IS NULL;
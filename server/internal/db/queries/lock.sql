@@ -0,0 +1,2 @@
+-- name: TryAdvisoryXactLock :one
+SELECT pg_try_advisory_xact_lock(sqlc.arg(lock_id)::BIGINT);
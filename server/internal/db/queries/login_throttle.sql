@@ -0,0 +1,37 @@
+-- name: GetLoginThrottle :one
+SELECT *
+FROM login_throttles
+WHERE scope = $1 AND subject = $2
+LIMIT 1;
+
+-- name: RecordLoginFailure :one
+-- Failures before reset_before are forgotten, counting starts over
+INSERT INTO login_throttles (
+    scope,
+    subject,
+    failures,
+    last_failed_at
+) VALUES (
+    $1, $2, 1, CURRENT_TIMESTAMP
+)
+ON CONFLICT (scope, subject) DO UPDATE
+SET failures = CASE
+        WHEN login_throttles.last_failed_at < sqlc.arg(reset_before) THEN 1
+        ELSE login_throttles.failures + 1
+    END,
+    last_failed_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: LockLoginThrottle :exec
+UPDATE login_throttles
+SET locked_until = $3
+WHERE scope = $1 AND subject = $2;
+
+-- name: DeleteLoginThrottle :exec
+DELETE FROM login_throttles
+WHERE scope = $1 AND subject = $2;
+
+-- name: DeleteExpiredLoginThrottles :execrows
+DELETE FROM login_throttles
+WHERE last_failed_at < $1
+AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP);
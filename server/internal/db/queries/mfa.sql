@@ -0,0 +1,82 @@
+-- name: UpsertUserTOTP :one
+-- Starts over an enrollment that was never confirmed, a confirmed authenticator is left alone
+INSERT INTO user_totp (
+    user_id,
+    secret
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = CURRENT_TIMESTAMP
+WHERE user_totp.confirmed_at IS NULL
+RETURNING *;
+
+-- name: GetUserTOTP :one
+SELECT *
+FROM user_totp
+WHERE user_id = $1
+LIMIT 1;
+
+-- name: ConfirmUserTOTP :execrows
+UPDATE user_totp
+SET confirmed_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND confirmed_at IS NULL;
+
+-- name: UseTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: DeleteUserTOTP :exec
+DELETE FROM user_totp
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO mfa_recovery_codes (
+    user_id,
+    code_hash
+) VALUES (
+    $1, $2
+);
+
+-- name: UseRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*)
+FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1;
+
+-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges (
+    user_id,
+    token,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetMFAChallengeByToken :one
+SELECT *
+FROM mfa_challenges
+WHERE token = $1
+LIMIT 1;
+
+-- name: RecordMFAChallengeAttempt :one
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING attempts;
+
+-- name: MarkMFAChallengeUsed :execrows
+UPDATE mfa_challenges
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
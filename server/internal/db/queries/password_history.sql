@@ -0,0 +1,26 @@
+-- name: CreatePasswordHistory :exec
+INSERT INTO password_history (
+    user_id,
+    password_hash
+) VALUES (
+    $1, $2
+);
+
+-- name: ListUserPasswordHistory :many
+SELECT password_hash
+FROM password_history
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: DeleteOldPasswordHistory :exec
+-- Keeps the latest hashes of the user
+DELETE FROM password_history
+WHERE user_id = $1
+AND id NOT IN (
+    SELECT id
+    FROM password_history
+    WHERE user_id = $1
+    ORDER BY created_at DESC
+    LIMIT sqlc.arg(keep)
+);
@@ -0,0 +1,31 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (
+    user_id,
+    token,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetPasswordResetTokenByToken :one
+SELECT *
+FROM password_reset_tokens
+WHERE token = $1
+LIMIT 1;
+
+-- name: GetLatestPasswordResetToken :one
+SELECT *
+FROM password_reset_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: MarkPasswordResetTokenUsed :execrows
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidateUserPasswordResetTokens :exec
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND used_at IS NULL;
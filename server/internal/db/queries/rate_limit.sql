@@ -0,0 +1,28 @@
+-- name: CreateRateLimitBucket :exec
+-- Buckets start out full, nothing happens when the bucket already exists
+INSERT INTO rate_limit_buckets (
+    key,
+    tokens,
+    updated_at,
+    expires_at
+) VALUES (
+    $1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+)
+ON CONFLICT (key) DO NOTHING;
+
+-- name: GetRateLimitBucketForUpdate :one
+-- Locks the bucket until the end of the transaction. The database clock is returned so that
+-- all server instances refill buckets at the same time.
+SELECT key, tokens, updated_at, CURRENT_TIMESTAMP::timestamptz AS now
+FROM rate_limit_buckets
+WHERE key = $1
+FOR UPDATE;
+
+-- name: UpdateRateLimitBucket :exec
+UPDATE rate_limit_buckets
+SET tokens = $2, updated_at = $3, expires_at = $4
+WHERE key = $1;
+
+-- name: DeleteExpiredRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets
+WHERE expires_at < CURRENT_TIMESTAMP;
@@ -0,0 +1,46 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_token (
+    user_id,
+    client_id,
+    token,
+    expires_at,
+    authorization_code_id,
+    family_id,
+    scope,
+    auth_time,
+    session_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: GetRefreshTokenByToken :one
+SELECT *
+FROM refresh_token
+WHERE token = $1
+LIMIT 1;
+
+-- name: RotateRefreshToken :execrows
+UPDATE refresh_token
+SET is_active = FALSE,
+    rotated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND is_active = TRUE AND rotated_at IS NULL;
+
+-- name: DeactivateRefreshTokenFamily :exec
+UPDATE refresh_token
+SET is_active = FALSE
+WHERE family_id = $1;
+
+-- name: DeactivateRefreshTokensByAuthorizationCode :exec
+UPDATE refresh_token
+SET is_active = FALSE
+WHERE authorization_code_id = $1;
+
+-- name: DeactivateUserClientRefreshTokens :exec
+UPDATE refresh_token
+SET is_active = FALSE
+WHERE user_id = $1 AND client_id = $2;
+
+-- name: DeactivateSessionRefreshTokens :exec
+UPDATE refresh_token
+SET is_active = FALSE
+WHERE session_id = ANY(sqlc.arg(session_ids)::UUID[]);
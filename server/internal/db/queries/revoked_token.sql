@@ -0,0 +1,18 @@
+-- name: RevokeAccessToken :exec
+INSERT INTO revoked_tokens (
+    jti,
+    expires_at
+) VALUES (
+    $1, $2
+) ON CONFLICT (jti) DO NOTHING;
+
+-- name: IsAccessTokenRevoked :one
+SELECT EXISTS (
+    SELECT 1
+    FROM revoked_tokens
+    WHERE jti = $1
+);
+
+-- name: DeleteExpiredRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < CURRENT_TIMESTAMP;
@@ -0,0 +1,26 @@
+-- name: AssignUserRole :exec
+-- Nothing happens when the user already has the role or no role has the name
+INSERT INTO user_roles (
+    user_id,
+    role_id
+)
+SELECT $1, id
+FROM roles
+WHERE name = sqlc.arg(role_name)
+ON CONFLICT (user_id, role_id) DO NOTHING;
+
+-- name: ListUserRoles :many
+SELECT roles.name
+FROM roles
+JOIN user_roles ON user_roles.role_id = roles.id
+WHERE user_roles.user_id = $1
+ORDER BY roles.name ASC;
+
+-- name: ListUserPermissions :many
+-- Permissions granted by any of the roles of the user
+SELECT DISTINCT permissions.name
+FROM permissions
+JOIN role_permissions ON role_permissions.permission_id = permissions.id
+JOIN user_roles ON user_roles.role_id = role_permissions.role_id
+WHERE user_roles.user_id = $1
+ORDER BY permissions.name ASC;
@@ -0,0 +1,27 @@
+-- name: ListScopes :many
+SELECT *
+FROM scopes
+ORDER BY name ASC;
+
+-- name: GetScopesByNames :many
+SELECT *
+FROM scopes
+WHERE name = ANY(sqlc.arg(names)::TEXT[])
+ORDER BY name ASC;
+
+-- name: UpsertScope :one
+INSERT INTO scopes (
+    name,
+    description,
+    requires_consent
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (name) DO UPDATE
+SET description = EXCLUDED.description,
+    requires_consent = EXCLUDED.requires_consent,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteScope :execrows
+DELETE FROM scopes
+WHERE name = $1;
@@ -4,9 +4,10 @@ INSERT INTO sessions (
     session_token,
     user_agent,
     ip_address,
-    expires_at
+    expires_at,
+    amr
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetSessionByToken :one
@@ -15,6 +16,12 @@ WHERE session_token = $1
 AND is_active = TRUE 
 AND expires_at > CURRENT_TIMESTAMP;
 
+-- name: GetSessionByID :one
+SELECT * FROM sessions
+WHERE id = $1
+AND is_active = TRUE
+AND expires_at > CURRENT_TIMESTAMP;
+
 -- name: DeactivateSession :exec
 UPDATE sessions 
 SET is_active = FALSE 
@@ -25,8 +32,42 @@ UPDATE sessions
 SET is_active = FALSE 
 WHERE user_id = $1;
 
+-- name: DeactivateOtherUserSessions :exec
+UPDATE sessions
+SET is_active = FALSE
+WHERE user_id = $1 AND id <> $2;
+
 -- name: CountActiveUserSessions :one
 SELECT COUNT(*) FROM sessions 
 WHERE user_id = $1 
 AND is_active = TRUE 
 AND expires_at > CURRENT_TIMESTAMP;
+
+-- name: ListActiveUserSessionIDs :many
+SELECT id FROM sessions
+WHERE user_id = $1
+AND is_active = TRUE
+AND expires_at > CURRENT_TIMESTAMP;
+
+-- name: ListSessionBackchannelLogoutClients :many
+SELECT
+    ac.session_id,
+    c.client_id,
+    c.backchannel_logout_uri,
+    c.backchannel_logout_session_required
+FROM authorization_code ac
+JOIN clients c ON c.id = ac.client_id
+WHERE ac.session_id = ANY(sqlc.arg(session_ids)::UUID[])
+AND c.is_active = TRUE
+AND c.backchannel_logout_uri IS NOT NULL
+UNION
+SELECT
+    dc.session_id,
+    c.client_id,
+    c.backchannel_logout_uri,
+    c.backchannel_logout_session_required
+FROM device_codes dc
+JOIN clients c ON c.id = dc.client_id
+WHERE dc.session_id = ANY(sqlc.arg(session_ids)::UUID[])
+AND c.is_active = TRUE
+AND c.backchannel_logout_uri IS NOT NULL;
@@ -0,0 +1,31 @@
+-- name: CreateSigningKey :one
+INSERT INTO signing_keys (
+    kid,
+    algorithm,
+    private_key,
+    activates_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetSigningKeyByKid :one
+SELECT *
+FROM signing_keys
+WHERE kid = $1
+LIMIT 1;
+
+-- name: ListSigningKeys :many
+SELECT *
+FROM signing_keys
+ORDER BY activates_at DESC;
+
+-- name: ListPublishedSigningKeys :many
+SELECT *
+FROM signing_keys
+WHERE retires_at IS NULL OR retires_at > CURRENT_TIMESTAMP
+ORDER BY activates_at ASC;
+
+-- name: SetSigningKeyRetiresAt :exec
+UPDATE signing_keys
+SET retires_at = $2
+WHERE id = $1;
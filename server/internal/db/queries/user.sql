@@ -22,4 +22,31 @@ INSERT INTO users (
     updated_at
 ) VALUES (
     $1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
-) RETURNING *;
\ No newline at end of file
+) RETURNING *;
+
+-- name: ConfirmUserEmail :execrows
+UPDATE users
+SET email = $2,
+    email_verified = TRUE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RehashUserPassword :execrows
+-- Only replaces the hash that was verified, a password changed in the meantime is kept
+UPDATE users
+SET password_hash = $2
+WHERE id = $1 AND password_hash = sqlc.arg(current_password_hash);
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET full_name = $2,
+    date_of_birth = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
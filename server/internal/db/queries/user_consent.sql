@@ -0,0 +1,36 @@
+-- name: GetUserConsent :one
+SELECT *
+FROM user_consents
+WHERE user_id = $1 AND client_id = $2
+LIMIT 1;
+
+-- name: UpsertUserConsent :one
+INSERT INTO user_consents (
+    user_id,
+    client_id,
+    scopes
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (user_id, client_id) DO UPDATE
+SET scopes = EXCLUDED.scopes,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListUserConsents :many
+SELECT
+    user_consents.id,
+    user_consents.scopes,
+    user_consents.created_at,
+    user_consents.updated_at,
+    clients.client_id,
+    clients.name,
+    clients.description,
+    clients.website_url
+FROM user_consents
+JOIN clients ON clients.id = user_consents.client_id
+WHERE user_consents.user_id = $1 AND clients.is_active = true
+ORDER BY user_consents.updated_at DESC;
+
+-- name: DeleteUserConsent :execrows
+DELETE FROM user_consents
+WHERE user_id = $1 AND client_id = $2;
@@ -0,0 +1,64 @@
+-- name: CreateWebAuthnCredential :one
+INSERT INTO webauthn_credentials (
+    user_id,
+    credential_id,
+    public_key,
+    sign_count,
+    transports,
+    aaguid,
+    name
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetWebAuthnCredentialByCredentialID :one
+SELECT *
+FROM webauthn_credentials
+WHERE credential_id = $1
+LIMIT 1;
+
+-- name: ListUserWebAuthnCredentials :many
+SELECT *
+FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: CountUserWebAuthnCredentials :one
+SELECT COUNT(*)
+FROM webauthn_credentials
+WHERE user_id = $1;
+
+-- name: UpdateWebAuthnCredentialUsage :exec
+UPDATE webauthn_credentials
+SET sign_count = $2,
+    last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteUserWebAuthnCredentials :exec
+DELETE FROM webauthn_credentials
+WHERE user_id = $1;
+
+-- name: CreateWebAuthnChallenge :one
+INSERT INTO webauthn_challenges (
+    user_id,
+    ceremony,
+    challenge,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebAuthnChallenge :one
+SELECT *
+FROM webauthn_challenges
+WHERE challenge = $1
+LIMIT 1;
+
+-- name: MarkWebAuthnChallengeUsed :execrows
+UPDATE webauthn_challenges
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
@@ -35,23 +35,43 @@ INSERT INTO clients (
     is_active,
     is_confidential,
     created_by,
+    grant_types,
+    allowed_scopes,
+    is_first_party,
+    token_endpoint_auth_method,
+    logo_uri,
+    jwks,
+    jwks_uri,
+    post_logout_redirect_uris,
+    backchannel_logout_uri,
+    backchannel_logout_session_required,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
-) RETURNING id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+) RETURNING id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at, grant_types, allowed_scopes, is_first_party, token_endpoint_auth_method, logo_uri, registration_access_token, jwks, jwks_uri, post_logout_redirect_uris, backchannel_logout_uri, backchannel_logout_session_required
 `
 
 type CreateClientParams struct {
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	ClientID       string         `json:"client_id"`
-	ClientSecret   string         `json:"client_secret"`
-	RedirectUris   []string       `json:"redirect_uris"`
-	WebsiteUrl     sql.NullString `json:"website_url"`
-	IsActive       sql.NullBool   `json:"is_active"`
-	IsConfidential sql.NullBool   `json:"is_confidential"`
-	CreatedBy      uuid.NullUUID  `json:"created_by"`
+	Name                             string         `json:"name"`
+	Description                      sql.NullString `json:"description"`
+	ClientID                         string         `json:"client_id"`
+	ClientSecret                     string         `json:"client_secret"`
+	RedirectUris                     []string       `json:"redirect_uris"`
+	WebsiteUrl                       sql.NullString `json:"website_url"`
+	IsActive                         sql.NullBool   `json:"is_active"`
+	IsConfidential                   sql.NullBool   `json:"is_confidential"`
+	CreatedBy                        uuid.NullUUID  `json:"created_by"`
+	GrantTypes                       []string       `json:"grant_types"`
+	AllowedScopes                    []string       `json:"allowed_scopes"`
+	IsFirstParty                     bool           `json:"is_first_party"`
+	TokenEndpointAuthMethod          string         `json:"token_endpoint_auth_method"`
+	LogoUri                          sql.NullString `json:"logo_uri"`
+	Jwks                             sql.NullString `json:"jwks"`
+	JwksUri                          sql.NullString `json:"jwks_uri"`
+	PostLogoutRedirectUris           []string       `json:"post_logout_redirect_uris"`
+	BackchannelLogoutUri             sql.NullString `json:"backchannel_logout_uri"`
+	BackchannelLogoutSessionRequired bool           `json:"backchannel_logout_session_required"`
 }
 
 func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Client, error) {
@@ -65,6 +85,16 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cli
 		arg.IsActive,
 		arg.IsConfidential,
 		arg.CreatedBy,
+		pq.Array(arg.GrantTypes),
+		pq.Array(arg.AllowedScopes),
+		arg.IsFirstParty,
+		arg.TokenEndpointAuthMethod,
+		arg.LogoUri,
+		arg.Jwks,
+		arg.JwksUri,
+		pq.Array(arg.PostLogoutRedirectUris),
+		arg.BackchannelLogoutUri,
+		arg.BackchannelLogoutSessionRequired,
 	)
 	var i Client
 	err := row.Scan(
@@ -80,6 +110,17 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cli
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		pq.Array(&i.GrantTypes),
+		pq.Array(&i.AllowedScopes),
+		&i.IsFirstParty,
+		&i.TokenEndpointAuthMethod,
+		&i.LogoUri,
+		&i.RegistrationAccessToken,
+		&i.Jwks,
+		&i.JwksUri,
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.BackchannelLogoutUri,
+		&i.BackchannelLogoutSessionRequired,
 	)
 	return i, err
 }
@@ -98,55 +139,45 @@ func (q *Queries) DeleteClient(ctx context.Context, id uuid.UUID) error {
 }
 
 const getAllClients = `-- name: GetAllClients :many
-SELECT 
-    id,
-    name,
-    description,
-    client_id,
-    redirect_uris,
-    website_url,
-    is_active,
-    is_confidential,
-    created_at,
-    updated_at
+SELECT id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at, grant_types, allowed_scopes, is_first_party, token_endpoint_auth_method, logo_uri, registration_access_token, jwks, jwks_uri, post_logout_redirect_uris, backchannel_logout_uri, backchannel_logout_session_required
 FROM clients
 WHERE is_active = true
 ORDER BY created_at DESC
 `
 
-type GetAllClientsRow struct {
-	ID             uuid.UUID      `json:"id"`
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	ClientID       string         `json:"client_id"`
-	RedirectUris   []string       `json:"redirect_uris"`
-	WebsiteUrl     sql.NullString `json:"website_url"`
-	IsActive       sql.NullBool   `json:"is_active"`
-	IsConfidential sql.NullBool   `json:"is_confidential"`
-	CreatedAt      sql.NullTime   `json:"created_at"`
-	UpdatedAt      sql.NullTime   `json:"updated_at"`
-}
-
-func (q *Queries) GetAllClients(ctx context.Context) ([]GetAllClientsRow, error) {
+func (q *Queries) GetAllClients(ctx context.Context) ([]Client, error) {
 	rows, err := q.db.QueryContext(ctx, getAllClients)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetAllClientsRow{}
+	items := []Client{}
 	for rows.Next() {
-		var i GetAllClientsRow
+		var i Client
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
 			&i.Description,
 			&i.ClientID,
+			&i.ClientSecret,
 			pq.Array(&i.RedirectUris),
 			&i.WebsiteUrl,
 			&i.IsActive,
 			&i.IsConfidential,
+			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			pq.Array(&i.GrantTypes),
+			pq.Array(&i.AllowedScopes),
+			&i.IsFirstParty,
+			&i.TokenEndpointAuthMethod,
+			&i.LogoUri,
+			&i.RegistrationAccessToken,
+			&i.Jwks,
+			&i.JwksUri,
+			pq.Array(&i.PostLogoutRedirectUris),
+			&i.BackchannelLogoutUri,
+			&i.BackchannelLogoutSessionRequired,
 		); err != nil {
 			return nil, err
 		}
@@ -162,7 +193,7 @@ func (q *Queries) GetAllClients(ctx context.Context) ([]GetAllClientsRow, error)
 }
 
 const getClientByClientId = `-- name: GetClientByClientId :one
-SELECT id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at
+SELECT id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at, grant_types, allowed_scopes, is_first_party, token_endpoint_auth_method, logo_uri, registration_access_token, jwks, jwks_uri, post_logout_redirect_uris, backchannel_logout_uri, backchannel_logout_session_required
 FROM clients
 WHERE client_id = $1 AND is_active = true
 LIMIT 1
@@ -184,54 +215,55 @@ func (q *Queries) GetClientByClientId(ctx context.Context, clientID string) (Cli
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		pq.Array(&i.GrantTypes),
+		pq.Array(&i.AllowedScopes),
+		&i.IsFirstParty,
+		&i.TokenEndpointAuthMethod,
+		&i.LogoUri,
+		&i.RegistrationAccessToken,
+		&i.Jwks,
+		&i.JwksUri,
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.BackchannelLogoutUri,
+		&i.BackchannelLogoutSessionRequired,
 	)
 	return i, err
 }
 
 const getClientById = `-- name: GetClientById :one
-SELECT 
-    id,
-    name,
-    description,
-    client_id,
-    redirect_uris,
-    website_url,
-    is_active,
-    is_confidential,
-    created_at,
-    updated_at
+SELECT id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at, grant_types, allowed_scopes, is_first_party, token_endpoint_auth_method, logo_uri, registration_access_token, jwks, jwks_uri, post_logout_redirect_uris, backchannel_logout_uri, backchannel_logout_session_required
 FROM clients
 WHERE id = $1 AND is_active = true
 LIMIT 1
 `
 
-type GetClientByIdRow struct {
-	ID             uuid.UUID      `json:"id"`
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	ClientID       string         `json:"client_id"`
-	RedirectUris   []string       `json:"redirect_uris"`
-	WebsiteUrl     sql.NullString `json:"website_url"`
-	IsActive       sql.NullBool   `json:"is_active"`
-	IsConfidential sql.NullBool   `json:"is_confidential"`
-	CreatedAt      sql.NullTime   `json:"created_at"`
-	UpdatedAt      sql.NullTime   `json:"updated_at"`
-}
-
-func (q *Queries) GetClientById(ctx context.Context, id uuid.UUID) (GetClientByIdRow, error) {
+func (q *Queries) GetClientById(ctx context.Context, id uuid.UUID) (Client, error) {
 	row := q.db.QueryRowContext(ctx, getClientById, id)
-	var i GetClientByIdRow
+	var i Client
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Description,
 		&i.ClientID,
+		&i.ClientSecret,
 		pq.Array(&i.RedirectUris),
 		&i.WebsiteUrl,
 		&i.IsActive,
 		&i.IsConfidential,
+		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		pq.Array(&i.GrantTypes),
+		pq.Array(&i.AllowedScopes),
+		&i.IsFirstParty,
+		&i.TokenEndpointAuthMethod,
+		&i.LogoUri,
+		&i.RegistrationAccessToken,
+		&i.Jwks,
+		&i.JwksUri,
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.BackchannelLogoutUri,
+		&i.BackchannelLogoutSessionRequired,
 	)
 	return i, err
 }
@@ -242,7 +274,7 @@ SET
     client_secret = $2,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND is_active = true
-RETURNING id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at
+RETURNING id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at, grant_types, allowed_scopes, is_first_party, token_endpoint_auth_method, logo_uri, registration_access_token, jwks, jwks_uri, post_logout_redirect_uris, backchannel_logout_uri, backchannel_logout_session_required
 `
 
 type RegenerateClientSecretParams struct {
@@ -266,6 +298,17 @@ func (q *Queries) RegenerateClientSecret(ctx context.Context, arg RegenerateClie
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		pq.Array(&i.GrantTypes),
+		pq.Array(&i.AllowedScopes),
+		&i.IsFirstParty,
+		&i.TokenEndpointAuthMethod,
+		&i.LogoUri,
+		&i.RegistrationAccessToken,
+		&i.Jwks,
+		&i.JwksUri,
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.BackchannelLogoutUri,
+		&i.BackchannelLogoutSessionRequired,
 	)
 	return i, err
 }
@@ -276,7 +319,7 @@ SET
     client_secret = $2,
     updated_at = CURRENT_TIMESTAMP
 WHERE client_id = $1 AND is_active = true
-RETURNING id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at
+RETURNING id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at, grant_types, allowed_scopes, is_first_party, token_endpoint_auth_method, logo_uri, registration_access_token, jwks, jwks_uri, post_logout_redirect_uris, backchannel_logout_uri, backchannel_logout_session_required
 `
 
 type RegenerateClientSecretByClientIdParams struct {
@@ -300,10 +343,39 @@ func (q *Queries) RegenerateClientSecretByClientId(ctx context.Context, arg Rege
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		pq.Array(&i.GrantTypes),
+		pq.Array(&i.AllowedScopes),
+		&i.IsFirstParty,
+		&i.TokenEndpointAuthMethod,
+		&i.LogoUri,
+		&i.RegistrationAccessToken,
+		&i.Jwks,
+		&i.JwksUri,
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.BackchannelLogoutUri,
+		&i.BackchannelLogoutSessionRequired,
 	)
 	return i, err
 }
 
+const setClientRegistrationAccessToken = `-- name: SetClientRegistrationAccessToken :exec
+UPDATE clients
+SET 
+    registration_access_token = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND is_active = true
+`
+
+type SetClientRegistrationAccessTokenParams struct {
+	ID                      uuid.UUID      `json:"id"`
+	RegistrationAccessToken sql.NullString `json:"registration_access_token"`
+}
+
+func (q *Queries) SetClientRegistrationAccessToken(ctx context.Context, arg SetClientRegistrationAccessTokenParams) error {
+	_, err := q.db.ExecContext(ctx, setClientRegistrationAccessToken, arg.ID, arg.RegistrationAccessToken)
+	return err
+}
+
 const updateClient = `-- name: UpdateClient :one
 UPDATE clients
 SET 
@@ -312,44 +384,41 @@ SET
     redirect_uris = $4,
     website_url = $5,
     is_confidential = $6,
+    grant_types = $7,
+    allowed_scopes = $8,
+    is_first_party = $9,
+    token_endpoint_auth_method = $10,
+    logo_uri = $11,
+    jwks = $12,
+    jwks_uri = $13,
+    post_logout_redirect_uris = $14,
+    backchannel_logout_uri = $15,
+    backchannel_logout_session_required = $16,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND is_active = true
-RETURNING 
-    id,
-    name,
-    description,
-    client_id,
-    redirect_uris,
-    website_url,
-    is_active,
-    is_confidential,
-    created_at,
-    updated_at
+RETURNING id, name, description, client_id, client_secret, redirect_uris, website_url, is_active, is_confidential, created_by, created_at, updated_at, grant_types, allowed_scopes, is_first_party, token_endpoint_auth_method, logo_uri, registration_access_token, jwks, jwks_uri, post_logout_redirect_uris, backchannel_logout_uri, backchannel_logout_session_required
 `
 
 type UpdateClientParams struct {
-	ID             uuid.UUID      `json:"id"`
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	RedirectUris   []string       `json:"redirect_uris"`
-	WebsiteUrl     sql.NullString `json:"website_url"`
-	IsConfidential sql.NullBool   `json:"is_confidential"`
-}
-
-type UpdateClientRow struct {
-	ID             uuid.UUID      `json:"id"`
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	ClientID       string         `json:"client_id"`
-	RedirectUris   []string       `json:"redirect_uris"`
-	WebsiteUrl     sql.NullString `json:"website_url"`
-	IsActive       sql.NullBool   `json:"is_active"`
-	IsConfidential sql.NullBool   `json:"is_confidential"`
-	CreatedAt      sql.NullTime   `json:"created_at"`
-	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	ID                               uuid.UUID      `json:"id"`
+	Name                             string         `json:"name"`
+	Description                      sql.NullString `json:"description"`
+	RedirectUris                     []string       `json:"redirect_uris"`
+	WebsiteUrl                       sql.NullString `json:"website_url"`
+	IsConfidential                   sql.NullBool   `json:"is_confidential"`
+	GrantTypes                       []string       `json:"grant_types"`
+	AllowedScopes                    []string       `json:"allowed_scopes"`
+	IsFirstParty                     bool           `json:"is_first_party"`
+	TokenEndpointAuthMethod          string         `json:"token_endpoint_auth_method"`
+	LogoUri                          sql.NullString `json:"logo_uri"`
+	Jwks                             sql.NullString `json:"jwks"`
+	JwksUri                          sql.NullString `json:"jwks_uri"`
+	PostLogoutRedirectUris           []string       `json:"post_logout_redirect_uris"`
+	BackchannelLogoutUri             sql.NullString `json:"backchannel_logout_uri"`
+	BackchannelLogoutSessionRequired bool           `json:"backchannel_logout_session_required"`
 }
 
-func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (UpdateClientRow, error) {
+func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Client, error) {
 	row := q.db.QueryRowContext(ctx, updateClient,
 		arg.ID,
 		arg.Name,
@@ -357,19 +426,42 @@ func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Upd
 		pq.Array(arg.RedirectUris),
 		arg.WebsiteUrl,
 		arg.IsConfidential,
+		pq.Array(arg.GrantTypes),
+		pq.Array(arg.AllowedScopes),
+		arg.IsFirstParty,
+		arg.TokenEndpointAuthMethod,
+		arg.LogoUri,
+		arg.Jwks,
+		arg.JwksUri,
+		pq.Array(arg.PostLogoutRedirectUris),
+		arg.BackchannelLogoutUri,
+		arg.BackchannelLogoutSessionRequired,
 	)
-	var i UpdateClientRow
+	var i Client
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Description,
 		&i.ClientID,
+		&i.ClientSecret,
 		pq.Array(&i.RedirectUris),
 		&i.WebsiteUrl,
 		&i.IsActive,
 		&i.IsConfidential,
+		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		pq.Array(&i.GrantTypes),
+		pq.Array(&i.AllowedScopes),
+		&i.IsFirstParty,
+		&i.TokenEndpointAuthMethod,
+		&i.LogoUri,
+		&i.RegistrationAccessToken,
+		&i.Jwks,
+		&i.JwksUri,
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.BackchannelLogoutUri,
+		&i.BackchannelLogoutSessionRequired,
 	)
 	return i, err
 }
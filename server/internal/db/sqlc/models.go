@@ -11,40 +11,191 @@ import (
 	"github.com/google/uuid"
 )
 
+type AccountUnlockToken struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
 type AuthorizationCode struct {
-	ID          uuid.UUID    `json:"id"`
-	UserID      uuid.UUID    `json:"user_id"`
-	ClientID    uuid.UUID    `json:"client_id"`
-	Code        string       `json:"code"`
-	RedirectUri string       `json:"redirect_uri"`
-	CreatedAt   sql.NullTime `json:"created_at"`
-	ExpiresAt   time.Time    `json:"expires_at"`
-	IsUsed      sql.NullBool `json:"is_used"`
+	ID                  uuid.UUID      `json:"id"`
+	UserID              uuid.UUID      `json:"user_id"`
+	ClientID            uuid.UUID      `json:"client_id"`
+	Code                string         `json:"code"`
+	RedirectUri         string         `json:"redirect_uri"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	ExpiresAt           time.Time      `json:"expires_at"`
+	IsUsed              sql.NullBool   `json:"is_used"`
+	Scope               string         `json:"scope"`
+	CodeChallenge       sql.NullString `json:"code_challenge"`
+	CodeChallengeMethod sql.NullString `json:"code_challenge_method"`
+	Nonce               sql.NullString `json:"nonce"`
+	AuthTime            sql.NullTime   `json:"auth_time"`
+	SessionID           uuid.NullUUID  `json:"session_id"`
+	RedirectUriSent     bool           `json:"redirect_uri_sent"`
+	AccessTokenJti      sql.NullString `json:"access_token_jti"`
 }
 
 type Client struct {
-	ID             uuid.UUID      `json:"id"`
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	ClientID       string         `json:"client_id"`
-	ClientSecret   string         `json:"client_secret"`
-	RedirectUris   []string       `json:"redirect_uris"`
-	WebsiteUrl     sql.NullString `json:"website_url"`
-	IsActive       sql.NullBool   `json:"is_active"`
-	IsConfidential sql.NullBool   `json:"is_confidential"`
-	CreatedBy      uuid.NullUUID  `json:"created_by"`
-	CreatedAt      sql.NullTime   `json:"created_at"`
-	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	ID                               uuid.UUID      `json:"id"`
+	Name                             string         `json:"name"`
+	Description                      sql.NullString `json:"description"`
+	ClientID                         string         `json:"client_id"`
+	ClientSecret                     string         `json:"client_secret"`
+	RedirectUris                     []string       `json:"redirect_uris"`
+	WebsiteUrl                       sql.NullString `json:"website_url"`
+	IsActive                         sql.NullBool   `json:"is_active"`
+	IsConfidential                   sql.NullBool   `json:"is_confidential"`
+	CreatedBy                        uuid.NullUUID  `json:"created_by"`
+	CreatedAt                        sql.NullTime   `json:"created_at"`
+	UpdatedAt                        sql.NullTime   `json:"updated_at"`
+	GrantTypes                       []string       `json:"grant_types"`
+	AllowedScopes                    []string       `json:"allowed_scopes"`
+	IsFirstParty                     bool           `json:"is_first_party"`
+	TokenEndpointAuthMethod          string         `json:"token_endpoint_auth_method"`
+	LogoUri                          sql.NullString `json:"logo_uri"`
+	RegistrationAccessToken          sql.NullString `json:"registration_access_token"`
+	Jwks                             sql.NullString `json:"jwks"`
+	JwksUri                          sql.NullString `json:"jwks_uri"`
+	PostLogoutRedirectUris           []string       `json:"post_logout_redirect_uris"`
+	BackchannelLogoutUri             sql.NullString `json:"backchannel_logout_uri"`
+	BackchannelLogoutSessionRequired bool           `json:"backchannel_logout_session_required"`
 }
 
-type RefreshToken struct {
+type ClientAssertionJti struct {
+	ClientID  uuid.UUID    `json:"client_id"`
+	Jti       string       `json:"jti"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type DeviceCode struct {
+	ID           uuid.UUID     `json:"id"`
+	ClientID     uuid.UUID     `json:"client_id"`
+	DeviceCode   string        `json:"device_code"`
+	UserCode     string        `json:"user_code"`
+	Scope        string        `json:"scope"`
+	Status       string        `json:"status"`
+	UserID       uuid.NullUUID `json:"user_id"`
+	AuthTime     sql.NullTime  `json:"auth_time"`
+	PollInterval int32         `json:"poll_interval"`
+	LastPolledAt sql.NullTime  `json:"last_polled_at"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+	CreatedAt    sql.NullTime  `json:"created_at"`
+	SessionID    uuid.NullUUID `json:"session_id"`
+}
+
+type EmailVerificationToken struct {
 	ID        uuid.UUID    `json:"id"`
 	UserID    uuid.UUID    `json:"user_id"`
-	ClientID  uuid.UUID    `json:"client_id"`
 	Token     string       `json:"token"`
+	Email     string       `json:"email"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
 	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type LoginThrottle struct {
+	Scope        string       `json:"scope"`
+	Subject      string       `json:"subject"`
+	Failures     int32        `json:"failures"`
+	LastFailedAt time.Time    `json:"last_failed_at"`
+	LockedUntil  sql.NullTime `json:"locked_until"`
+}
+
+type MfaChallenge struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Token     string       `json:"token"`
+	Attempts  int32        `json:"attempts"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type MfaRecoveryCode struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	CodeHash  string       `json:"code_hash"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type PasswordHistory struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       uuid.UUID `json:"user_id"`
+	PasswordHash string    `json:"password_hash"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Token     string       `json:"token"`
 	ExpiresAt time.Time    `json:"expires_at"`
-	IsActive  sql.NullBool `json:"is_active"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type Permission struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type RefreshToken struct {
+	ID                  uuid.UUID     `json:"id"`
+	UserID              uuid.UUID     `json:"user_id"`
+	ClientID            uuid.UUID     `json:"client_id"`
+	Token               string        `json:"token"`
+	CreatedAt           sql.NullTime  `json:"created_at"`
+	ExpiresAt           time.Time     `json:"expires_at"`
+	IsActive            sql.NullBool  `json:"is_active"`
+	AuthorizationCodeID uuid.NullUUID `json:"authorization_code_id"`
+	FamilyID            uuid.UUID     `json:"family_id"`
+	Scope               string        `json:"scope"`
+	RotatedAt           sql.NullTime  `json:"rotated_at"`
+	AuthTime            sql.NullTime  `json:"auth_time"`
+	SessionID           uuid.NullUUID `json:"session_id"`
+}
+
+type RevokedToken struct {
+	Jti       string       `json:"jti"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+}
+
+type Role struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
+}
+
+type RolePermission struct {
+	RoleID       uuid.UUID `json:"role_id"`
+	PermissionID uuid.UUID `json:"permission_id"`
+}
+
+type Scope struct {
+	ID              uuid.UUID    `json:"id"`
+	Name            string       `json:"name"`
+	Description     string       `json:"description"`
+	RequiresConsent bool         `json:"requires_consent"`
+	CreatedAt       sql.NullTime `json:"created_at"`
+	UpdatedAt       sql.NullTime `json:"updated_at"`
 }
 
 type Session struct {
@@ -56,6 +207,17 @@ type Session struct {
 	CreatedAt    sql.NullTime   `json:"created_at"`
 	ExpiresAt    time.Time      `json:"expires_at"`
 	IsActive     sql.NullBool   `json:"is_active"`
+	Amr          []string       `json:"amr"`
+}
+
+type SigningKey struct {
+	ID          uuid.UUID    `json:"id"`
+	Kid         string       `json:"kid"`
+	Algorithm   string       `json:"algorithm"`
+	PrivateKey  []byte       `json:"private_key"`
+	ActivatesAt time.Time    `json:"activates_at"`
+	RetiresAt   sql.NullTime `json:"retires_at"`
+	CreatedAt   sql.NullTime `json:"created_at"`
 }
 
 type User struct {
@@ -69,3 +231,49 @@ type User struct {
 	CreatedAt     sql.NullTime `json:"created_at"`
 	UpdatedAt     sql.NullTime `json:"updated_at"`
 }
+
+type UserConsent struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	ClientID  uuid.UUID    `json:"client_id"`
+	Scopes    []string     `json:"scopes"`
+	CreatedAt sql.NullTime `json:"created_at"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+type UserRole struct {
+	UserID    uuid.UUID    `json:"user_id"`
+	RoleID    uuid.UUID    `json:"role_id"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type UserTotp struct {
+	UserID       uuid.UUID    `json:"user_id"`
+	Secret       []byte       `json:"secret"`
+	ConfirmedAt  sql.NullTime `json:"confirmed_at"`
+	LastUsedStep int64        `json:"last_used_step"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+}
+
+type WebauthnChallenge struct {
+	ID        uuid.UUID     `json:"id"`
+	UserID    uuid.NullUUID `json:"user_id"`
+	Ceremony  string        `json:"ceremony"`
+	Challenge string        `json:"challenge"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	UsedAt    sql.NullTime  `json:"used_at"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+}
+
+type WebauthnCredential struct {
+	ID           uuid.UUID    `json:"id"`
+	UserID       uuid.UUID    `json:"user_id"`
+	CredentialID []byte       `json:"credential_id"`
+	PublicKey    []byte       `json:"public_key"`
+	SignCount    int64        `json:"sign_count"`
+	Transports   []string     `json:"transports"`
+	Aaguid       []byte       `json:"aaguid"`
+	Name         string       `json:"name"`
+	LastUsedAt   sql.NullTime `json:"last_used_at"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+}
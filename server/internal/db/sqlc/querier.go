@@ -6,28 +6,137 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	ApproveDeviceCode(ctx context.Context, arg ApproveDeviceCodeParams) (int64, error)
+	// Nothing happens when the user already has the role or no role has the name
+	AssignUserRole(ctx context.Context, arg AssignUserRoleParams) error
+	ConfirmUserEmail(ctx context.Context, arg ConfirmUserEmailParams) (int64, error)
+	ConfirmUserTOTP(ctx context.Context, userID uuid.UUID) (int64, error)
+	ConsumeDeviceCode(ctx context.Context, id uuid.UUID) (int64, error)
 	CountActiveUserSessions(ctx context.Context, userID uuid.UUID) (int64, error)
 	CountClients(ctx context.Context) (int64, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountUserWebAuthnCredentials(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateAccountUnlockToken(ctx context.Context, arg CreateAccountUnlockTokenParams) (AccountUnlockToken, error)
+	CreateAuthorizationCode(ctx context.Context, arg CreateAuthorizationCodeParams) (AuthorizationCode, error)
 	CreateClient(ctx context.Context, arg CreateClientParams) (Client, error)
+	CreateDeviceCode(ctx context.Context, arg CreateDeviceCodeParams) (DeviceCode, error)
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) (EmailVerificationToken, error)
+	CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) (MfaChallenge, error)
+	CreatePasswordHistory(ctx context.Context, arg CreatePasswordHistoryParams) error
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	// Buckets start out full, nothing happens when the bucket already exists
+	CreateRateLimitBucket(ctx context.Context, arg CreateRateLimitBucketParams) error
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSigningKey(ctx context.Context, arg CreateSigningKeyParams) (SigningKey, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebAuthnChallenge(ctx context.Context, arg CreateWebAuthnChallengeParams) (WebauthnChallenge, error)
+	CreateWebAuthnCredential(ctx context.Context, arg CreateWebAuthnCredentialParams) (WebauthnCredential, error)
 	DeactivateAllUserSessions(ctx context.Context, userID uuid.UUID) error
+	DeactivateOtherUserSessions(ctx context.Context, arg DeactivateOtherUserSessionsParams) error
+	DeactivateRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	DeactivateRefreshTokensByAuthorizationCode(ctx context.Context, authorizationCodeID uuid.NullUUID) error
 	DeactivateSession(ctx context.Context, sessionToken string) error
+	DeactivateSessionRefreshTokens(ctx context.Context, sessionIds []uuid.UUID) error
+	DeactivateUserClientRefreshTokens(ctx context.Context, arg DeactivateUserClientRefreshTokensParams) error
 	DeleteClient(ctx context.Context, id uuid.UUID) error
-	GetAllClients(ctx context.Context) ([]GetAllClientsRow, error)
+	DeleteExpiredClientAssertionJTIs(ctx context.Context) (int64, error)
+	DeleteExpiredDeviceCodes(ctx context.Context) (int64, error)
+	DeleteExpiredLoginThrottles(ctx context.Context, lastFailedAt time.Time) (int64, error)
+	DeleteExpiredRateLimitBuckets(ctx context.Context) (int64, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) (int64, error)
+	DeleteLoginThrottle(ctx context.Context, arg DeleteLoginThrottleParams) error
+	// Keeps the latest hashes of the user
+	DeleteOldPasswordHistory(ctx context.Context, arg DeleteOldPasswordHistoryParams) error
+	DeleteScope(ctx context.Context, name string) (int64, error)
+	DeleteUserConsent(ctx context.Context, arg DeleteUserConsentParams) (int64, error)
+	DeleteUserRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error
+	DeleteUserWebAuthnCredentials(ctx context.Context, userID uuid.UUID) error
+	DeleteWebAuthnCredential(ctx context.Context, arg DeleteWebAuthnCredentialParams) (int64, error)
+	DenyDeviceCode(ctx context.Context, id uuid.UUID) (int64, error)
+	GetAccountUnlockTokenByToken(ctx context.Context, token string) (AccountUnlockToken, error)
+	GetAllClients(ctx context.Context) ([]Client, error)
+	GetAuthorizationCodeByCode(ctx context.Context, code string) (AuthorizationCode, error)
 	GetClientByClientId(ctx context.Context, clientID string) (Client, error)
-	GetClientById(ctx context.Context, id uuid.UUID) (GetClientByIdRow, error)
+	GetClientById(ctx context.Context, id uuid.UUID) (Client, error)
+	GetDeviceCodeByDeviceCode(ctx context.Context, deviceCode string) (DeviceCode, error)
+	GetDeviceCodeByUserCode(ctx context.Context, userCode string) (DeviceCode, error)
+	GetEmailVerificationTokenByToken(ctx context.Context, token string) (EmailVerificationToken, error)
+	GetLatestEmailVerificationToken(ctx context.Context, userID uuid.UUID) (EmailVerificationToken, error)
+	GetLatestPasswordResetToken(ctx context.Context, userID uuid.UUID) (PasswordResetToken, error)
+	GetLoginThrottle(ctx context.Context, arg GetLoginThrottleParams) (LoginThrottle, error)
+	GetMFAChallengeByToken(ctx context.Context, token string) (MfaChallenge, error)
+	GetPasswordResetTokenByToken(ctx context.Context, token string) (PasswordResetToken, error)
+	// Locks the bucket until the end of the transaction. The database clock is returned so that
+	// all server instances refill buckets at the same time.
+	GetRateLimitBucketForUpdate(ctx context.Context, key string) (GetRateLimitBucketForUpdateRow, error)
+	GetRefreshTokenByToken(ctx context.Context, token string) (RefreshToken, error)
+	GetScopesByNames(ctx context.Context, names []string) ([]Scope, error)
+	GetSessionByID(ctx context.Context, id uuid.UUID) (Session, error)
 	GetSessionByToken(ctx context.Context, sessionToken string) (Session, error)
+	GetSigningKeyByKid(ctx context.Context, kid string) (SigningKey, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	GetUserConsent(ctx context.Context, arg GetUserConsentParams) (UserConsent, error)
+	GetUserTOTP(ctx context.Context, userID uuid.UUID) (UserTotp, error)
+	GetWebAuthnChallenge(ctx context.Context, challenge string) (WebauthnChallenge, error)
+	GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (WebauthnCredential, error)
+	InvalidateUserAccountUnlockTokens(ctx context.Context, userID uuid.UUID) error
+	InvalidateUserEmailVerificationTokens(ctx context.Context, userID uuid.UUID) error
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	ListActiveUserSessionIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	ListPublishedSigningKeys(ctx context.Context) ([]SigningKey, error)
+	ListScopes(ctx context.Context) ([]Scope, error)
+	ListSessionBackchannelLogoutClients(ctx context.Context, sessionIds []uuid.UUID) ([]ListSessionBackchannelLogoutClientsRow, error)
+	ListSigningKeys(ctx context.Context) ([]SigningKey, error)
+	ListUserConsents(ctx context.Context, userID uuid.UUID) ([]ListUserConsentsRow, error)
+	ListUserPasswordHistory(ctx context.Context, arg ListUserPasswordHistoryParams) ([]string, error)
+	// Permissions granted by any of the roles of the user
+	ListUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+	ListUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+	ListUserWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]WebauthnCredential, error)
+	LockLoginThrottle(ctx context.Context, arg LockLoginThrottleParams) error
+	MarkAccountUnlockTokenUsed(ctx context.Context, id uuid.UUID) (int64, error)
+	// Records the access token issued for the code, so that it can be revoked when the code is replayed
+	MarkAuthorizationCodeUsed(ctx context.Context, arg MarkAuthorizationCodeUsedParams) (int64, error)
+	MarkEmailVerificationTokenUsed(ctx context.Context, id uuid.UUID) (int64, error)
+	MarkMFAChallengeUsed(ctx context.Context, id uuid.UUID) (int64, error)
+	MarkPasswordResetTokenUsed(ctx context.Context, id uuid.UUID) (int64, error)
+	MarkWebAuthnChallengeUsed(ctx context.Context, id uuid.UUID) (int64, error)
+	RecordClientAssertionJTI(ctx context.Context, arg RecordClientAssertionJTIParams) (int64, error)
+	// Failures before reset_before are forgotten, counting starts over
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (LoginThrottle, error)
+	RecordMFAChallengeAttempt(ctx context.Context, id uuid.UUID) (int32, error)
 	RegenerateClientSecret(ctx context.Context, arg RegenerateClientSecretParams) (Client, error)
 	RegenerateClientSecretByClientId(ctx context.Context, arg RegenerateClientSecretByClientIdParams) (Client, error)
-	UpdateClient(ctx context.Context, arg UpdateClientParams) (UpdateClientRow, error)
+	// Only replaces the hash that was verified, a password changed in the meantime is kept
+	RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error)
+	RevokeAccessToken(ctx context.Context, arg RevokeAccessTokenParams) error
+	RotateRefreshToken(ctx context.Context, id uuid.UUID) (int64, error)
+	SetClientRegistrationAccessToken(ctx context.Context, arg SetClientRegistrationAccessTokenParams) error
+	SetSigningKeyRetiresAt(ctx context.Context, arg SetSigningKeyRetiresAtParams) error
+	TryAdvisoryXactLock(ctx context.Context, lockID int64) (bool, error)
+	UpdateClient(ctx context.Context, arg UpdateClientParams) (Client, error)
+	UpdateDeviceCodePoll(ctx context.Context, arg UpdateDeviceCodePollParams) error
+	UpdateRateLimitBucket(ctx context.Context, arg UpdateRateLimitBucketParams) error
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UpdateWebAuthnCredentialUsage(ctx context.Context, arg UpdateWebAuthnCredentialUsageParams) error
+	UpsertScope(ctx context.Context, arg UpsertScopeParams) (Scope, error)
+	UpsertUserConsent(ctx context.Context, arg UpsertUserConsentParams) (UserConsent, error)
+	// Starts over an enrollment that was never confirmed, a confirmed authenticator is left alone
+	UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTotp, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
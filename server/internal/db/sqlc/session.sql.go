@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const countActiveUserSessions = `-- name: CountActiveUserSessions :one
@@ -33,10 +34,11 @@ INSERT INTO sessions (
     session_token,
     user_agent,
     ip_address,
-    expires_at
+    expires_at,
+    amr
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, user_id, session_token, user_agent, ip_address, created_at, expires_at, is_active
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, user_id, session_token, user_agent, ip_address, created_at, expires_at, is_active, amr
 `
 
 type CreateSessionParams struct {
@@ -45,6 +47,7 @@ type CreateSessionParams struct {
 	UserAgent    sql.NullString `json:"user_agent"`
 	IpAddress    sql.NullString `json:"ip_address"`
 	ExpiresAt    time.Time      `json:"expires_at"`
+	Amr          []string       `json:"amr"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -54,6 +57,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.UserAgent,
 		arg.IpAddress,
 		arg.ExpiresAt,
+		pq.Array(arg.Amr),
 	)
 	var i Session
 	err := row.Scan(
@@ -65,6 +69,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.CreatedAt,
 		&i.ExpiresAt,
 		&i.IsActive,
+		pq.Array(&i.Amr),
 	)
 	return i, err
 }
@@ -80,6 +85,22 @@ func (q *Queries) DeactivateAllUserSessions(ctx context.Context, userID uuid.UUI
 	return err
 }
 
+const deactivateOtherUserSessions = `-- name: DeactivateOtherUserSessions :exec
+UPDATE sessions
+SET is_active = FALSE
+WHERE user_id = $1 AND id <> $2
+`
+
+type DeactivateOtherUserSessionsParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) DeactivateOtherUserSessions(ctx context.Context, arg DeactivateOtherUserSessionsParams) error {
+	_, err := q.db.ExecContext(ctx, deactivateOtherUserSessions, arg.UserID, arg.ID)
+	return err
+}
+
 const deactivateSession = `-- name: DeactivateSession :exec
 UPDATE sessions 
 SET is_active = FALSE 
@@ -91,8 +112,32 @@ func (q *Queries) DeactivateSession(ctx context.Context, sessionToken string) er
 	return err
 }
 
+const getSessionByID = `-- name: GetSessionByID :one
+SELECT id, user_id, session_token, user_agent, ip_address, created_at, expires_at, is_active, amr FROM sessions
+WHERE id = $1
+AND is_active = TRUE
+AND expires_at > CURRENT_TIMESTAMP
+`
+
+func (q *Queries) GetSessionByID(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, getSessionByID, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.SessionToken,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.IsActive,
+		pq.Array(&i.Amr),
+	)
+	return i, err
+}
+
 const getSessionByToken = `-- name: GetSessionByToken :one
-SELECT id, user_id, session_token, user_agent, ip_address, created_at, expires_at, is_active FROM sessions 
+SELECT id, user_id, session_token, user_agent, ip_address, created_at, expires_at, is_active, amr FROM sessions 
 WHERE session_token = $1 
 AND is_active = TRUE 
 AND expires_at > CURRENT_TIMESTAMP
@@ -110,6 +155,96 @@ func (q *Queries) GetSessionByToken(ctx context.Context, sessionToken string) (S
 		&i.CreatedAt,
 		&i.ExpiresAt,
 		&i.IsActive,
+		pq.Array(&i.Amr),
 	)
 	return i, err
 }
+
+const listActiveUserSessionIDs = `-- name: ListActiveUserSessionIDs :many
+SELECT id FROM sessions
+WHERE user_id = $1
+AND is_active = TRUE
+AND expires_at > CURRENT_TIMESTAMP
+`
+
+func (q *Queries) ListActiveUserSessionIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveUserSessionIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSessionBackchannelLogoutClients = `-- name: ListSessionBackchannelLogoutClients :many
+SELECT
+    ac.session_id,
+    c.client_id,
+    c.backchannel_logout_uri,
+    c.backchannel_logout_session_required
+FROM authorization_code ac
+JOIN clients c ON c.id = ac.client_id
+WHERE ac.session_id = ANY($1::UUID[])
+AND c.is_active = TRUE
+AND c.backchannel_logout_uri IS NOT NULL
+UNION
+SELECT
+    dc.session_id,
+    c.client_id,
+    c.backchannel_logout_uri,
+    c.backchannel_logout_session_required
+FROM device_codes dc
+JOIN clients c ON c.id = dc.client_id
+WHERE dc.session_id = ANY($1::UUID[])
+AND c.is_active = TRUE
+AND c.backchannel_logout_uri IS NOT NULL
+`
+
+type ListSessionBackchannelLogoutClientsRow struct {
+	SessionID                        uuid.NullUUID  `json:"session_id"`
+	ClientID                         string         `json:"client_id"`
+	BackchannelLogoutUri             sql.NullString `json:"backchannel_logout_uri"`
+	BackchannelLogoutSessionRequired bool           `json:"backchannel_logout_session_required"`
+}
+
+func (q *Queries) ListSessionBackchannelLogoutClients(ctx context.Context, sessionIds []uuid.UUID) ([]ListSessionBackchannelLogoutClientsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionBackchannelLogoutClients, pq.Array(sessionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSessionBackchannelLogoutClientsRow{}
+	for rows.Next() {
+		var i ListSessionBackchannelLogoutClientsRow
+		if err := rows.Scan(
+			&i.SessionID,
+			&i.ClientID,
+			&i.BackchannelLogoutUri,
+			&i.BackchannelLogoutSessionRequired,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -12,6 +12,27 @@ import (
 	"github.com/google/uuid"
 )
 
+const confirmUserEmail = `-- name: ConfirmUserEmail :execrows
+UPDATE users
+SET email = $2,
+    email_verified = TRUE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type ConfirmUserEmailParams struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+func (q *Queries) ConfirmUserEmail(ctx context.Context, arg ConfirmUserEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, confirmUserEmail, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     email,
@@ -107,3 +128,73 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 	)
 	return i, err
 }
+
+const rehashUserPassword = `-- name: RehashUserPassword :execrows
+UPDATE users
+SET password_hash = $2
+WHERE id = $1 AND password_hash = $3
+`
+
+type RehashUserPasswordParams struct {
+	ID                  uuid.UUID `json:"id"`
+	PasswordHash        string    `json:"password_hash"`
+	CurrentPasswordHash string    `json:"current_password_hash"`
+}
+
+// Only replaces the hash that was verified, a password changed in the meantime is kept
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rehashUserPassword, arg.ID, arg.PasswordHash, arg.CurrentPasswordHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           uuid.UUID `json:"id"`
+	PasswordHash string    `json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET full_name = $2,
+    date_of_birth = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, email, password_hash, full_name, date_of_birth, email_verified, active, created_at, updated_at
+`
+
+type UpdateUserProfileParams struct {
+	ID          uuid.UUID    `json:"id"`
+	FullName    string       `json:"full_name"`
+	DateOfBirth sql.NullTime `json:"date_of_birth"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile, arg.ID, arg.FullName, arg.DateOfBirth)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FullName,
+		&i.DateOfBirth,
+		&i.EmailVerified,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// currentUserID returns the ID of the user authenticated by the auth middleware
+func currentUserID(c echo.Context) (uuid.UUID, bool) {
+	userIDStr, _ := c.Get("user_id").(string)
+	userID, err := uuid.Parse(userIDStr)
+	return userID, err == nil
+}
+
+// backgroundEmailTimeout bounds sending an email after the response has gone out
+const backgroundEmailTimeout = time.Minute
+
+// sendInBackground sends an email about an account after the response has gone out, so that
+// neither a failure nor the time sending takes tells whether an address has an account.
+// Failures are logged with the failure message.
+func sendInBackground(ctx context.Context, failure string, send func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundEmailTimeout)
+	go func() {
+		defer cancel()
+		if err := send(ctx); err != nil {
+			log.Printf("%s: %v", failure, err)
+		}
+	}()
+}
+
+// isOldEnough reports whether someone born on the given date is older than 12 years
+func isOldEnough(dateOfBirth time.Time) bool {
+	return !dateOfBirth.AddDate(13, 0, 0).After(time.Now())
+}
+
+// endUserSessions logs a user out of every active session but keepSessionID, which may be
+// uuid.Nil, and of the applications the sessions were used with. It returns how many sessions
+// were ended.
+func (h *AuthHandler) endUserSessions(ctx context.Context, userID, keepSessionID uuid.UUID) (int64, error) {
+	activeIDs, err := h.store.ListActiveUserSessionIDs(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	sessionIDs := make([]uuid.UUID, 0, len(activeIDs))
+	for _, id := range activeIDs {
+		if id != keepSessionID {
+			sessionIDs = append(sessionIDs, id)
+		}
+	}
+
+	if err := h.logoutNotifier.SessionsEnded(ctx, userID, sessionIDs); err != nil {
+		return 0, err
+	}
+
+	if keepSessionID == uuid.Nil {
+		err = h.store.DeactivateAllUserSessions(ctx, userID)
+	} else {
+		err = h.store.DeactivateOtherUserSessions(ctx, sqlc.DeactivateOtherUserSessionsParams{
+			UserID: userID,
+			ID:     keepSessionID,
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(sessionIDs)), nil
+}
@@ -1,5 +1,11 @@
 package auth
 
+import (
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/webauthn"
+)
+
 // ==========
 // Auth DTOs
 // ==========
@@ -7,7 +13,7 @@ package auth
 // === Register Dto ===
 type RegisterRequest struct {
 	Email       string `json:"email" validate:"required,email,max=255"`
-	Password    string `json:"password" validate:"required,min=8,max=72"`
+	Password    string `json:"password" validate:"required,password"`
 	FullName    string `json:"full_name" validate:"required,min=2,max=255"`
 	DateOfBirth string `json:"date_of_birth" validate:"required,datetime=2006-01-02"`
 }
@@ -19,12 +25,38 @@ type RegisterResponse struct {
 // === Login Dto ===
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email,max=255"`
-	Password string `json:"password" validate:"required,min=8,max=72"`
+	Password string `json:"password" validate:"required,max=1024"`
 }
 
 type LoginResponse struct {
 	// set session token in cookie and return user ID
-	AccessToken string `json:"access_token"`
+	AccessToken string `json:"access_token,omitempty"`
+	// Set instead when the user has to complete the login with a second factor
+	MFARequired bool     `json:"mfa_required,omitempty"`
+	MFAToken    string   `json:"mfa_token,omitempty"`
+	MFAMethods  []string `json:"mfa_methods,omitempty"`
+}
+
+// === Login MFA Dto ===
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required,max=255"`
+	// TOTP code or recovery code
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// === Login MFA WebAuthn Dto ===
+type BeginLoginMFAWebAuthnRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required,max=255"`
+}
+
+type LoginMFAWebAuthnRequest struct {
+	MFAToken   string                     `json:"mfa_token" validate:"required,max=255"`
+	Credential webauthn.AssertionResponse `json:"credential"`
+}
+
+// === Passkey Login Dto ===
+type PasskeyLoginRequest struct {
+	Credential webauthn.AssertionResponse `json:"credential"`
 }
 
 // === Logout Dto ===
@@ -48,6 +80,159 @@ type LogoutAllResponse struct {
 	SessionsEnded int    `json:"sessions_ended"`
 }
 
+// === Verify Email Dto ===
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,max=255"`
+}
+
+type VerifyEmailResponse struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// === Resend Verification Email Dto ===
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+type ResendVerificationResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// === Forgot Password Dto ===
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+type ForgotPasswordResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// === Reset Password Dto ===
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,password"`
+}
+
+type ResetPasswordResponse struct {
+	Success         bool  `json:"success"`
+	SessionsRevoked int64 `json:"sessions_revoked"`
+}
+
+// === Unlock Account Dto ===
+type UnlockAccountRequest struct {
+	Token string `json:"token" validate:"required,max=255"`
+}
+
+type UnlockAccountResponse struct {
+	Success bool   `json:"success"`
+	UserID  string `json:"user_id"`
+}
+
+// === Profile Dto ===
+type ProfileResponse struct {
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	FullName      string    `json:"full_name"`
+	DateOfBirth   string    `json:"date_of_birth,omitempty"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// === Update Profile Dto ===
+type UpdateProfileRequest struct {
+	// Fields left out are not changed
+	FullName    *string `json:"full_name" validate:"omitnil,min=2,max=255"`
+	DateOfBirth *string `json:"date_of_birth" validate:"omitnil,datetime=2006-01-02"`
+}
+
+// === Change Password Dto ===
+type ChangePasswordRequest struct {
+	CurrentPassword     string `json:"current_password" validate:"required,max=1024"`
+	NewPassword         string `json:"new_password" validate:"required,password"`
+	LogoutOtherSessions bool   `json:"logout_other_sessions"`
+}
+
+type ChangePasswordResponse struct {
+	Success         bool  `json:"success"`
+	SessionsRevoked int64 `json:"sessions_revoked"`
+}
+
+// === Change Email Dto ===
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email" validate:"required,email,max=255"`
+	CurrentPassword string `json:"current_password" validate:"required,max=1024"`
+}
+
+type ChangeEmailResponse struct {
+	Success      bool   `json:"success"`
+	Message      string `json:"message"`
+	PendingEmail string `json:"pending_email"`
+}
+
+// === MFA Status Dto ===
+type MFAStatusResponse struct {
+	Enabled                bool     `json:"enabled"`
+	Methods                []string `json:"methods"`
+	RecoveryCodesRemaining int64    `json:"recovery_codes_remaining"`
+}
+
+// === Enroll TOTP Dto ===
+type EnrollTOTPRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=1024"`
+}
+
+type EnrollTOTPResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// === Confirm TOTP Dto ===
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// === Regenerate Recovery Codes / Disable MFA Dto ===
+type MFAReauthRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=1024"`
+	// TOTP code or recovery code
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// === WebAuthn Credential Dto ===
+type WebAuthnCredentialResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Transports []string   `json:"transports"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type BeginWebAuthnRegistrationRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=1024"`
+	// TOTP code or recovery code, required once MFA is enabled
+	Code string `json:"code" validate:"max=32"`
+}
+
+type FinishWebAuthnRegistrationRequest struct {
+	// Label the user recognizes the security key or passkey by
+	Name       string                        `json:"name" validate:"required,max=255"`
+	Credential webauthn.RegistrationResponse `json:"credential"`
+}
+
+type FinishWebAuthnRegistrationResponse struct {
+	Credential WebAuthnCredentialResponse `json:"credential"`
+	// Only set when the credential enabled MFA, this is the only time they are shown
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
 // === Refresh Token Dto ===
 type RefreshTokenRequest struct {
 	// No additional fields needed as session token comes from cookie
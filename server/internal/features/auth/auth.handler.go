@@ -4,17 +4,23 @@ import (
 	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/features"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features/oauth"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/mailer"
 )
 
 type AuthHandler struct {
-	store  *db.Store
-	config *config.Config
+	store          *db.Store
+	config         *config.Config
+	mailer         mailer.Mailer
+	logoutNotifier *oauth.LogoutNotifier // Ends the client sessions of logged out login sessions
 }
 
 // NewAuthHandler creates a new authentication handler
 func NewAuthHandler(ah *features.AppHandlers) *AuthHandler {
 	return &AuthHandler{
-		store:  ah.Store,
-		config: ah.Cfg,
+		store:          ah.Store,
+		config:         ah.Cfg,
+		mailer:         ah.Mailer,
+		logoutNotifier: oauth.NewLogoutNotifier(ah.Store),
 	}
 }
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// ChangeEmail starts changing the email of the authenticated user. A verification link is sent
+// to the new address, which only replaces the current one once the link is opened.
+func (h *AuthHandler) ChangeEmail(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	// Parse the request body
+	req := new(ChangeEmailRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	user, err := h.store.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respondWithUnauthenticated(c)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch user",
+			err,
+		)
+	}
+
+	if !utils.ComparePasswords(user.PasswordHash, req.CurrentPassword) {
+		return respondWithWrongPassword(c)
+	}
+
+	if strings.EqualFold(req.NewEmail, user.Email) {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Email unchanged",
+			utils.ErrorCodeInvalidRequest,
+			"The new email address is the current one",
+			map[string]any{
+				"new_email": "The new email address is the current one",
+			},
+		)
+	}
+
+	// Check if the email already exists
+	_, err = h.store.GetUserByEmail(ctx, req.NewEmail)
+	if err == nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeConflict,
+			"Email already in use",
+			utils.ErrorCodeDuplicateEntry,
+			"User with this email already exists",
+			map[string]any{
+				"new_email": "Email already exists",
+			},
+		)
+	} else if err != sql.ErrNoRows {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not check if email exists",
+			err,
+		)
+	}
+
+	if err := h.sendVerificationEmail(ctx, user, req.NewEmail); err != nil {
+		return utils.RespondWithInternalError(c, "Failed to send verification email", err)
+	}
+
+	// Create the response
+	res := ChangeEmailResponse{
+		Success:      true,
+		Message:      "Open the link sent to the new address to confirm the change",
+		PendingEmail: req.NewEmail,
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Verification email sent",
+		res,
+	)
+}
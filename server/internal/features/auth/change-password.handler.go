@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ChangePassword sets a new password for the authenticated user after confirming the current
+// one. The other sessions of the user can be logged out along with it.
+func (h *AuthHandler) ChangePassword(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	// Parse the request body
+	req := new(ChangePasswordRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	user, err := h.store.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respondWithUnauthenticated(c)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch user",
+			err,
+		)
+	}
+
+	if !utils.ComparePasswords(user.PasswordHash, req.CurrentPassword) {
+		return respondWithWrongPassword(c)
+	}
+
+	// The new password must not contain personal information or reuse a previous one
+	if ok, err := h.validateNewPassword(c, req, user); !ok {
+		return err
+	}
+
+	// Hash the new password
+	hashedPassword, err := utils.Hash(req.NewPassword)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeInternalError,
+			"Could not hash password",
+			err,
+		)
+	}
+
+	// Outstanding reset links would undo the change
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.UpdateUserPassword(ctx, sqlc.UpdateUserPasswordParams{
+			ID:           user.ID,
+			PasswordHash: hashedPassword,
+		}); err != nil {
+			return err
+		}
+		if err := h.recordPasswordHistory(ctx, q, user.ID, hashedPassword); err != nil {
+			return err
+		}
+		return q.InvalidateUserPasswordResetTokens(ctx, user.ID)
+	})
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not change password",
+			err,
+		)
+	}
+
+	// The session the request came from stays logged in
+	var sessionsRevoked int64
+	if req.LogoutOtherSessions {
+		currentSessionID := uuid.Nil
+		if cookie, err := c.Cookie("session_token"); err == nil {
+			session, err := h.store.GetSessionByToken(ctx, cookie.Value)
+			if err == nil && session.UserID == user.ID {
+				currentSessionID = session.ID
+			}
+		}
+
+		sessionsRevoked, err = h.endUserSessions(ctx, user.ID, currentSessionID)
+		if err != nil {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeInternalError,
+				"Internal server error",
+				utils.ErrorCodeDatabaseError,
+				"Failed to revoke sessions",
+				err,
+			)
+		}
+	}
+
+	if err := h.sendPasswordChangedEmail(ctx, user, c.RealIP(), req.LogoutOtherSessions); err != nil {
+		log.Printf("Failed to send password changed email to user %s: %v", user.ID, err)
+	}
+
+	// Create the response
+	res := ChangePasswordResponse{
+		Success:         true,
+		SessionsRevoked: sessionsRevoked,
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Password changed successfully",
+		res,
+	)
+}
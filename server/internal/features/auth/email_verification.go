@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/mailer"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+)
+
+// sendVerificationEmail replaces the outstanding verification links of a user with a new one
+// and emails it to the address being verified. Only the hash of the token is stored.
+func (h *AuthHandler) sendVerificationEmail(ctx context.Context, user sqlc.User, email string) error {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.InvalidateUserEmailVerificationTokens(ctx, user.ID); err != nil {
+			return err
+		}
+		_, err := q.CreateEmailVerificationToken(ctx, sqlc.CreateEmailVerificationTokenParams{
+			UserID:    user.ID,
+			Token:     utils.HashToken(token),
+			Email:     email,
+			ExpiresAt: time.Now().Add(h.config.Auth.EmailVerificationExpiry),
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	link, err := url.Parse(h.config.Auth.EmailVerificationURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	msg, err := mailer.NewTemplateMessage(email, mailer.TemplateEmailVerification, mailer.LinkData{
+		Name:      user.FullName,
+		Link:      link.String(),
+		ExpiresIn: h.config.Auth.EmailVerificationExpiry,
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.mailer.Send(ctx, msg)
+}
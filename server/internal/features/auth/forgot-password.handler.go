@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// ForgotPassword emails a password reset link. The response is the same whether or not the
+// address belongs to an account, and throttled requests are dropped silently for the same reason.
+func (h *AuthHandler) ForgotPassword(c echo.Context) error {
+	// Parse the request body
+	req := new(ForgotPasswordRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	res := ForgotPasswordResponse{
+		Success: true,
+		Message: "If the address belongs to an account, a password reset link has been sent",
+	}
+
+	user, err := h.store.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, res.Message, res)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch user",
+			err,
+		)
+	}
+
+	// Disabled accounts cannot get back in by resetting their password
+	if user.Active.Valid && !user.Active.Bool {
+		return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, res.Message, res)
+	}
+
+	// Only one password reset email is sent per request interval
+	latest, err := h.store.GetLatestPasswordResetToken(ctx, user.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch password reset token",
+			err,
+		)
+	}
+	if err == nil && latest.CreatedAt.Valid && time.Since(latest.CreatedAt.Time) < h.config.Auth.PasswordResetRequestInterval {
+		return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, res.Message, res)
+	}
+
+	// The response is the same whether or not the address has an account
+	sendInBackground(ctx, fmt.Sprintf("Failed to send password reset email to user %s", user.ID), func(ctx context.Context) error {
+		return h.sendPasswordResetEmail(ctx, user)
+	})
+
+	return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, res.Message, res)
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/webauthn"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// BeginLoginMFAWebAuthn starts the second login step with a security key or passkey. The options
+// are passed to navigator.credentials.get() and the result to LoginMFAWebAuthn.
+func (h *AuthHandler) BeginLoginMFAWebAuthn(c echo.Context) error {
+	// Parse the request body
+	req := new(BeginLoginMFAWebAuthnRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request data
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	challenge, err := h.findMFAChallenge(c, req.MFAToken)
+	if challenge == nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	credentials, err := h.store.ListUserWebAuthnCredentials(ctx, challenge.UserID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch security keys", err)
+	}
+	if len(credentials) == 0 {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"No security keys",
+			utils.ErrorCodeInvalidRequest,
+			"No security keys are registered, use another method",
+			nil,
+		)
+	}
+
+	webauthnChallenge, err := h.createWebAuthnChallenge(ctx, uuid.NullUUID{UUID: challenge.UserID, Valid: true}, webauthnCeremonyMFA)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to create WebAuthn challenge", err)
+	}
+
+	res := h.config.WebAuthn.RequestOptions(webauthnChallenge, credentialDescriptors(credentials), webauthn.UserVerificationPreferred)
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Use a security key to complete the login",
+		res,
+	)
+}
+
+// LoginMFAWebAuthn completes the login of a user with MFA with a security key or passkey
+func (h *AuthHandler) LoginMFAWebAuthn(c echo.Context) error {
+	// Parse the request body
+	req := new(LoginMFAWebAuthnRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request data
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	challenge, err := h.findMFAChallenge(c, req.MFAToken)
+	if challenge == nil {
+		return err
+	}
+	user, err := h.mfaChallengeUser(c, challenge)
+	if user == nil {
+		return err
+	}
+	if ok, err := h.recordMFAAttempt(c, challenge); !ok {
+		return err
+	}
+
+	userID := uuid.NullUUID{UUID: challenge.UserID, Valid: true}
+	if _, err := h.verifyWebAuthnAssertion(c.Request().Context(), req.Credential, userID, webauthnCeremonyMFA); err != nil {
+		if errors.Is(err, errInvalidWebAuthnCredential) {
+			if err := h.recordLoginFailure(c.Request().Context(), user.Email, c.RealIP(), user); err != nil {
+				return utils.RespondWithInternalError(c, "Failed to record failed login", err)
+			}
+		}
+		return respondWithWebAuthnError(c, err)
+	}
+
+	return h.completeMFALogin(c, challenge, *user, []string{amrPassword, amrHardwareKey})
+}
@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// respondWithMFAChallenge answers a login with a correct password of a user with MFA. No session
+// is created yet, the returned token lets the client complete the login with LoginMFA or
+// LoginMFAWebAuthn, depending on the methods the user has set up.
+func (h *AuthHandler) respondWithMFAChallenge(c echo.Context, user sqlc.User, methods []string) error {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeInternalError,
+			"Failed to generate MFA token",
+			err,
+		)
+	}
+
+	_, err = h.store.CreateMFAChallenge(c.Request().Context(), sqlc.CreateMFAChallengeParams{
+		UserID:    user.ID,
+		Token:     utils.HashToken(token),
+		ExpiresAt: time.Now().Add(h.config.Auth.MFAChallengeExpiry),
+	})
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeDatabaseError,
+			"Failed to create MFA challenge",
+			err,
+		)
+	}
+
+	res := LoginResponse{
+		MFARequired: true,
+		MFAToken:    token,
+		MFAMethods:  methods,
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Multi-factor authentication required",
+		res,
+	)
+}
+
+// LoginMFA completes the login of a user with MFA with a TOTP code or a recovery code
+func (h *AuthHandler) LoginMFA(c echo.Context) error {
+	// Parse the request body
+	req := new(LoginMFARequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request data
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	challenge, err := h.findMFAChallenge(c, req.MFAToken)
+	if challenge == nil {
+		return err
+	}
+	user, err := h.mfaChallengeUser(c, challenge)
+	if user == nil {
+		return err
+	}
+	if ok, err := h.recordMFAAttempt(c, challenge); !ok {
+		return err
+	}
+
+	valid, err := h.verifySecondFactor(c.Request().Context(), challenge.UserID, req.Code)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeInternalError,
+			"Failed to verify code",
+			err,
+		)
+	}
+	if !valid {
+		if err := h.recordLoginFailure(c.Request().Context(), user.Email, c.RealIP(), user); err != nil {
+			return utils.RespondWithInternalError(c, "Failed to record failed login", err)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeUnauthorized,
+			"Unauthorized",
+			utils.ErrorCodeUnauthorized,
+			"Invalid authentication code",
+			map[string]any{
+				"code": "Invalid authentication code",
+			},
+		)
+	}
+
+	return h.completeMFALogin(c, challenge, *user, []string{amrPassword, amrOTP})
+}
+
+// findMFAChallenge looks up the pending second login step a token was handed out for. When no
+// challenge is returned the error response has already been sent and its result is returned
+// instead.
+func (h *AuthHandler) findMFAChallenge(c echo.Context, token string) (*sqlc.MfaChallenge, error) {
+	// Tokens are stored hashed
+	challenge, err := h.store.GetMFAChallengeByToken(c.Request().Context(), utils.HashToken(token))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeDatabaseError,
+			"Failed to fetch MFA challenge",
+			err,
+		)
+	}
+	if err == sql.ErrNoRows || challenge.UsedAt.Valid || time.Now().After(challenge.ExpiresAt) {
+		return nil, respondWithInvalidMFAChallenge(c)
+	}
+	return &challenge, nil
+}
+
+// mfaChallengeUser looks up the user a challenge was handed out to and refuses the attempt when
+// their account or the IP address is locked out, wrong codes count as failed logins. When no
+// user is returned the error response has already been sent and its result is returned instead.
+func (h *AuthHandler) mfaChallengeUser(c echo.Context, challenge *sqlc.MfaChallenge) (*sqlc.User, error) {
+	user, err := h.store.GetUserByID(c.Request().Context(), challenge.UserID)
+	if err != nil {
+		return nil, utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeDatabaseError,
+			"Failed to fetch user",
+			err,
+		)
+	}
+	if ok, err := h.checkLoginThrottle(c, user.Email); !ok {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// recordMFAAttempt counts an attempt at the second login step. Every attempt counts, guessing
+// needs a new password login once the attempts are used up. When false is returned the error
+// response has already been sent and its result is returned instead.
+func (h *AuthHandler) recordMFAAttempt(c echo.Context, challenge *sqlc.MfaChallenge) (bool, error) {
+	attempts, err := h.store.RecordMFAChallengeAttempt(c.Request().Context(), challenge.ID)
+	if err != nil {
+		return false, utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeDatabaseError,
+			"Failed to record MFA attempt",
+			err,
+		)
+	}
+	if int(attempts) > h.config.Auth.MFAChallengeMaxAttempts {
+		return false, utils.RespondWithError(
+			c,
+			utils.StatusCodeTooManyRequests,
+			"Too many attempts",
+			utils.ErrorCodeRateLimitExceeded,
+			"Too many failed attempts, please log in again",
+			nil,
+		)
+	}
+	return true, nil
+}
+
+// completeMFALogin creates the session of a user who passed the second login step. amr lists
+// the authentication methods the user logged in with.
+func (h *AuthHandler) completeMFALogin(c echo.Context, challenge *sqlc.MfaChallenge, user sqlc.User, amr []string) error {
+	ctx := c.Request().Context()
+
+	// Only one session can be created per challenge
+	used, err := h.store.MarkMFAChallengeUsed(ctx, challenge.ID)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeDatabaseError,
+			"Failed to complete MFA challenge",
+			err,
+		)
+	}
+	if used == 0 {
+		return respondWithInvalidMFAChallenge(c)
+	}
+
+	accessToken, err := h.startSession(c, user, amr)
+	if accessToken == "" {
+		return err
+	}
+
+	// Create the response
+	res := LoginResponse{
+		AccessToken: accessToken,
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Login successful",
+		res,
+	)
+}
+
+func respondWithInvalidMFAChallenge(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeUnauthorized,
+		"Unauthorized",
+		utils.ErrorCodeUnauthorized,
+		"The login has expired, please log in again",
+		nil,
+	)
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/webauthn"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// BeginPasskeyLogin starts a passwordless login. No user is named, the options let the browser
+// offer every passkey the user has for this site and the result is passed to PasskeyLogin.
+func (h *AuthHandler) BeginPasskeyLogin(c echo.Context) error {
+	challenge, err := h.createWebAuthnChallenge(c.Request().Context(), uuid.NullUUID{}, webauthnCeremonyPasskey)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to create WebAuthn challenge", err)
+	}
+
+	res := h.config.WebAuthn.RequestOptions(challenge, nil, webauthn.UserVerificationRequired)
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Use a passkey to log in",
+		res,
+	)
+}
+
+// PasskeyLogin logs a user in with a passkey instead of email and password. The authenticator
+// verified the user with a PIN or biometrics, so no second factor is asked for.
+func (h *AuthHandler) PasskeyLogin(c echo.Context) error {
+	// Parse the request body
+	req := new(PasskeyLoginRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request data
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	credential, err := h.verifyWebAuthnAssertion(ctx, req.Credential, uuid.NullUUID{}, webauthnCeremonyPasskey)
+	if err != nil {
+		return respondWithWebAuthnError(c, err)
+	}
+
+	user, err := h.store.GetUserByID(ctx, credential.UserID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch user", err)
+	}
+
+	// Unverified users cannot log in when email verification is required
+	if h.mustVerifyEmail(user) {
+		return respondWithEmailNotVerified(c)
+	}
+
+	accessToken, err := h.startSession(c, user, []string{amrHardwareKey, amrMultiFactor})
+	if accessToken == "" {
+		return err
+	}
+
+	// Create the response
+	res := LoginResponse{
+		AccessToken: accessToken,
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Login successful",
+		res,
+	)
+}
@@ -1,10 +1,13 @@
 package auth
 
 import (
+	"context"
 	"database/sql"
+	"log"
 	"net/http"
 	"time"
 
+	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
 	"github.com/labstack/echo/v4"
@@ -28,9 +31,19 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		return err
 	}
 
+	// Refuse logins of locked out accounts and IP addresses before looking at the password
+	if ok, err := h.checkLoginThrottle(c, req.Email); !ok {
+		return err
+	}
+
 	// check if user exists
 	user, err := h.store.GetUserByEmail(c.Request().Context(), req.Email)
 	if err != nil {
+		// Unknown email addresses take as long as wrong passwords
+		utils.SimulatePasswordComparison(req.Password)
+		if err := h.recordLoginFailure(c.Request().Context(), req.Email, c.RealIP(), nil); err != nil {
+			return utils.RespondWithInternalError(c, "Failed to record failed login", err)
+		}
 		return utils.RespondWithError(
 			c,
 			utils.StatusCodeUnauthorized,
@@ -42,6 +55,9 @@ func (h *AuthHandler) Login(c echo.Context) error {
 	}
 	// Verify password
 	if isValid := utils.ComparePasswords(user.PasswordHash, req.Password); !isValid {
+		if err := h.recordLoginFailure(c.Request().Context(), req.Email, c.RealIP(), &user); err != nil {
+			return utils.RespondWithInternalError(c, "Failed to record failed login", err)
+		}
 		// If password is invalid, return unauthorized error
 		return utils.RespondWithError(
 			c,
@@ -52,30 +68,92 @@ func (h *AuthHandler) Login(c echo.Context) error {
 			nil,
 		)
 	}
-	// Create access token claims
-	claims := utils.AccessTokenClaims{
-		UserID:        user.ID.String(),
-		Email:         user.Email,
-		FullName:      user.FullName,
-		EmailVerified: user.EmailVerified.Valid && user.EmailVerified.Bool,
+	// Hashes with an outdated algorithm or parameters are replaced while the password is at hand
+	if err := h.rehashPassword(c.Request().Context(), user, req.Password); err != nil {
+		log.Printf("Failed to rehash password of user %s: %v", user.ID, err)
 	}
-
-	// Create the access token
-	accessToken, _, err := utils.CreateAccessToken(claims)
+	// Unverified users cannot log in when email verification is required
+	if h.mustVerifyEmail(user) {
+		return respondWithEmailNotVerified(c)
+	}
+	// Users with MFA complete the login with a second factor before a session is created
+	methods, err := mfaMethods(c.Request().Context(), h.store.Queries, user.ID)
 	if err != nil {
 		return utils.RespondWithError(
 			c,
 			utils.StatusCodeInternalError,
 			"Internal Server Error",
-			utils.ErrorCodeInternalError,
-			"Failed to create access token",
+			utils.ErrorCodeDatabaseError,
+			"Failed to check multi-factor authentication",
 			err,
 		)
 	}
+	if len(methods) > 0 {
+		return h.respondWithMFAChallenge(c, user, methods)
+	}
+
+	accessToken, err := h.startSession(c, user, []string{amrPassword})
+	if accessToken == "" {
+		return err
+	}
+
+	// Create the response
+	res := LoginResponse{
+		AccessToken: accessToken,
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Login successful",
+		res,
+	)
+}
+
+// rehashPassword hashes the verified password of a user again when their stored hash is outdated
+func (h *AuthHandler) rehashPassword(ctx context.Context, user sqlc.User, password string) error {
+	if !utils.PasswordNeedsRehash(user.PasswordHash) {
+		return nil
+	}
+
+	hashedPassword, err := utils.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.store.RehashUserPassword(ctx, sqlc.RehashUserPasswordParams{
+		ID:                  user.ID,
+		PasswordHash:        hashedPassword,
+		CurrentPasswordHash: user.PasswordHash,
+	})
+	return err
+}
+
+// mustVerifyEmail reports whether the user has to verify their email address before logging in
+func (h *AuthHandler) mustVerifyEmail(user sqlc.User) bool {
+	return h.config.Auth.EmailVerificationPolicy == config.EmailVerificationRequired &&
+		!(user.EmailVerified.Valid && user.EmailVerified.Bool)
+}
+
+func respondWithEmailNotVerified(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeForbidden,
+		"Email not verified",
+		utils.ErrorCodeEmailNotVerified,
+		"Please verify your email address before logging in",
+		nil,
+	)
+}
 
+// startSession creates a login session for an authenticated user and sets the session and
+// access token cookies. amr lists the authentication methods the user logged in with. When no
+// access token is returned the error response has already been sent and its result is returned
+// instead.
+func (h *AuthHandler) startSession(c echo.Context, user sqlc.User, amr []string) (string, error) {
 	sessionToken, err := utils.GenerateRandomString(32) // Generate a random session
 	if err != nil {
-		return utils.RespondWithError(
+		return "", utils.RespondWithError(
 			c,
 			utils.StatusCodeInternalError,
 			"Internal Server Error",
@@ -84,6 +162,12 @@ func (h *AuthHandler) Login(c echo.Context) error {
 			err,
 		)
 	}
+	// The login is complete, earlier failures no longer count. Until then a correct password
+	// keeps them, so that guessing the second factor runs into the same lockout.
+	if err := h.clearAccountThrottle(c.Request().Context(), user.Email); err != nil {
+		log.Printf("Failed to clear failed logins of user %s: %v", user.ID, err)
+	}
+
 	userAgent := c.Request().UserAgent()
 	ipAddress := c.RealIP()
 
@@ -94,9 +178,10 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		UserAgent:    sql.NullString{String: userAgent, Valid: userAgent != ""},
 		IpAddress:    sql.NullString{String: ipAddress, Valid: ipAddress != ""},
 		ExpiresAt:    time.Now().Add(30 * 24 * time.Hour), // set expired after a months
+		Amr:          amr,
 	})
 	if err != nil {
-		return utils.RespondWithError(
+		return "", utils.RespondWithError(
 			c,
 			utils.StatusCodeInternalError,
 			"Internal Server Error",
@@ -106,14 +191,52 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		)
 	}
 
+	roles, permissions, err := h.rolesAndPermissions(c.Request().Context(), user.ID)
+	if err != nil {
+		return "", utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeDatabaseError,
+			"Failed to fetch roles",
+			err,
+		)
+	}
+
+	// Create access token claims
+	claims := utils.AccessTokenClaims{
+		UserID:        user.ID.String(),
+		Email:         user.Email,
+		FullName:      user.FullName,
+		EmailVerified: user.EmailVerified.Valid && user.EmailVerified.Bool,
+		SessionID:     session.ID.String(),
+		AMR:           amr,
+		Roles:         roles,
+		Permissions:   permissions,
+	}
+
+	// Create the access token
+	accessToken, _, err := utils.CreateAccessToken(claims)
+	if err != nil {
+		return "", utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeInternalError,
+			"Failed to create access token",
+			err,
+		)
+	}
+
 	// Set the session token in the cookie
+	// Lax so the session is sent along when an application redirects to /oauth/authorize
 	c.SetCookie(&http.Cookie{
 		Name:     "session_token",
 		Value:    session.SessionToken,
 		Path:     "/",
 		HttpOnly: true,
 		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
+		SameSite: http.SameSiteLaxMode,
 	})
 
 	// set access token in the cookie
@@ -126,16 +249,5 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		SameSite: http.SameSiteStrictMode,
 	})
 
-	// Create the response
-	res := LoginResponse{
-		AccessToken: accessToken,
-	}
-
-	return utils.RespondWithSuccess(
-		c,
-		utils.StatusCodeSuccess,
-		"Login successful",
-		res,
-	)
-
+	return accessToken, nil
 }
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/mailer"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// Login throttle scopes. Accounts are tracked by email address, so that addresses without an
+// account are locked out just the same and lockouts do not tell which addresses have one.
+const (
+	throttleScopeAccount = "account"
+	throttleScopeIP      = "ip"
+)
+
+// accountThrottleSubject normalizes an email address for tracking the failed logins of its account
+func accountThrottleSubject(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// checkLoginThrottle refuses a login when the account or the IP address is locked out or has
+// to wait after its latest failed login. When false is returned the error response has already
+// been sent and its result is returned instead.
+func (h *AuthHandler) checkLoginThrottle(c echo.Context, email string) (bool, error) {
+	ctx := c.Request().Context()
+	now := time.Now()
+
+	var retryAt time.Time
+	for _, params := range []sqlc.GetLoginThrottleParams{
+		{Scope: throttleScopeAccount, Subject: accountThrottleSubject(email)},
+		{Scope: throttleScopeIP, Subject: c.RealIP()},
+	} {
+		throttle, err := h.store.GetLoginThrottle(ctx, params)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return false, utils.RespondWithInternalError(c, "Failed to check failed logins", err)
+		}
+
+		if throttle.LockedUntil.Valid && throttle.LockedUntil.Time.After(retryAt) {
+			retryAt = throttle.LockedUntil.Time
+		}
+		// Accounts wait longer after every failure, IP addresses are shared by many users
+		// and only locked out
+		if params.Scope == throttleScopeAccount && throttle.LastFailedAt.After(now.Add(-h.config.Auth.LoginFailureWindow)) {
+			if delayedUntil := throttle.LastFailedAt.Add(h.loginDelay(throttle.Failures)); delayedUntil.After(retryAt) {
+				retryAt = delayedUntil
+			}
+		}
+	}
+
+	if !now.Before(retryAt) {
+		return true, nil
+	}
+
+	retryAfter := int64(math.Ceil(retryAt.Sub(now).Seconds()))
+	c.Response().Header().Set("Retry-After", fmt.Sprint(retryAfter))
+	return false, utils.RespondWithError(
+		c,
+		utils.StatusCodeTooManyRequests,
+		"Too many attempts",
+		utils.ErrorCodeRateLimitExceeded,
+		"Too many failed login attempts, please try again later",
+		map[string]any{
+			"retry_after": retryAfter,
+		},
+	)
+}
+
+// loginDelay is how long an account waits after its latest failed login. It doubles with every
+// failure, up to LoginDelayMax.
+func (h *AuthHandler) loginDelay(failures int32) time.Duration {
+	delay := h.config.Auth.LoginDelayBase
+	if delay <= 0 || failures <= 0 {
+		return 0
+	}
+	for i := int32(1); i < failures && delay < h.config.Auth.LoginDelayMax; i++ {
+		delay *= 2
+	}
+	return min(delay, h.config.Auth.LoginDelayMax)
+}
+
+// recordLoginFailure counts a failed login against the account and the IP address and locks
+// them out once they reach their threshold. user is nil when no account has the email address.
+// Users are emailed an unlock link when their account gets locked.
+func (h *AuthHandler) recordLoginFailure(ctx context.Context, email, ipAddress string, user *sqlc.User) error {
+	now := time.Now()
+	resetBefore := now.Add(-h.config.Auth.LoginFailureWindow)
+
+	// Failures that no longer count are not needed anymore
+	if _, err := h.store.DeleteExpiredLoginThrottles(ctx, resetBefore); err != nil {
+		log.Printf("Failed to delete expired login throttles: %v", err)
+	}
+
+	for _, limit := range []struct {
+		scope       string
+		subject     string
+		maxFailures int
+	}{
+		{throttleScopeAccount, accountThrottleSubject(email), h.config.Auth.LoginMaxFailures},
+		{throttleScopeIP, ipAddress, h.config.Auth.LoginIPMaxFailures},
+	} {
+		throttle, err := h.store.RecordLoginFailure(ctx, sqlc.RecordLoginFailureParams{
+			Scope:       limit.scope,
+			Subject:     limit.subject,
+			ResetBefore: resetBefore,
+		})
+		if err != nil {
+			return err
+		}
+		if int(throttle.Failures) < limit.maxFailures || (throttle.LockedUntil.Valid && throttle.LockedUntil.Time.After(now)) {
+			continue
+		}
+
+		err = h.store.LockLoginThrottle(ctx, sqlc.LockLoginThrottleParams{
+			Scope:       limit.scope,
+			Subject:     limit.subject,
+			LockedUntil: sql.NullTime{Time: now.Add(h.config.Auth.LoginLockoutDuration), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Locked out logins of %s %q after %d failures", limit.scope, limit.subject, throttle.Failures)
+
+		// Sending takes long enough to tell existing accounts apart, so it happens in the background
+		if limit.scope == throttleScopeAccount && user != nil && h.config.Auth.LoginUnlockByEmail {
+			locked := *user
+			sendInBackground(ctx, fmt.Sprintf("Failed to send account unlock email to user %s", locked.ID), func(ctx context.Context) error {
+				return h.sendAccountUnlockEmail(ctx, locked)
+			})
+		}
+	}
+	return nil
+}
+
+// clearAccountThrottle forgets the failed logins of an account and lifts its lockout
+func (h *AuthHandler) clearAccountThrottle(ctx context.Context, email string) error {
+	return h.store.DeleteLoginThrottle(ctx, sqlc.DeleteLoginThrottleParams{
+		Scope:   throttleScopeAccount,
+		Subject: accountThrottleSubject(email),
+	})
+}
+
+// sendAccountUnlockEmail replaces the outstanding unlock links of a user with a new one and
+// emails it to them. Only the hash of the token is stored.
+func (h *AuthHandler) sendAccountUnlockEmail(ctx context.Context, user sqlc.User) error {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.InvalidateUserAccountUnlockTokens(ctx, user.ID); err != nil {
+			return err
+		}
+		_, err := q.CreateAccountUnlockToken(ctx, sqlc.CreateAccountUnlockTokenParams{
+			UserID:    user.ID,
+			Token:     utils.HashToken(token),
+			ExpiresAt: time.Now().Add(h.config.Auth.AccountUnlockExpiry),
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	link, err := url.Parse(h.config.Auth.AccountUnlockURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	msg, err := mailer.NewTemplateMessage(user.Email, mailer.TemplateAccountLocked, mailer.LinkData{
+		Name:      user.FullName,
+		Link:      link.String(),
+		ExpiresIn: h.config.Auth.AccountUnlockExpiry,
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.mailer.Send(ctx, msg)
+}
@@ -48,28 +48,15 @@ func (h *AuthHandler) LogoutAll(c echo.Context) error {
 		)
 	}
 
-	// Count active sessions before deactivating them
-	activeSessions, err := h.store.CountActiveUserSessions(c.Request().Context(), userID)
+	// Log the user out of every session and the applications they were used with
+	activeSessions, err := h.endUserSessions(c.Request().Context(), userID, uuid.Nil)
 	if err != nil {
 		return utils.RespondWithError(
 			c,
 			utils.StatusCodeInternalError,
 			"Internal Server Error",
 			utils.ErrorCodeDatabaseError,
-			"Failed to count active sessions",
-			err,
-		)
-	}
-
-	// Deactivate all sessions for the user
-	err = h.store.DeactivateAllUserSessions(c.Request().Context(), userID)
-	if err != nil {
-		return utils.RespondWithError(
-			c,
-			utils.StatusCodeInternalError,
-			"Internal Server Error",
-			utils.ErrorCodeDatabaseError,
-			"Failed to deactivate sessions",
+			"Failed to end sessions",
 			err,
 		)
 	}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -52,6 +53,19 @@ func (h *AuthHandler) Logout(c echo.Context) error {
 			)
 		}
 	} else {
+		// Log the user out of the applications the session was used with
+		err = h.logoutNotifier.SessionsEnded(c.Request().Context(), session.UserID, []uuid.UUID{session.ID})
+		if err != nil {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeInternalError,
+				"Internal Server Error",
+				utils.ErrorCodeDatabaseError,
+				"Failed to end application sessions",
+				err,
+			)
+		}
+
 		// Deactivate the session in database
 		err = h.store.DeactivateSession(c.Request().Context(), session.SessionToken)
 		if err != nil {
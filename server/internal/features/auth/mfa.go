@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+)
+
+// Authentication method references (RFC 8176) recorded on login sessions
+const (
+	amrPassword    = "pwd"
+	amrOTP         = "otp"
+	amrHardwareKey = "hwk"
+	amrMultiFactor = "mfa"
+)
+
+// Second factors a login can be completed with, recovery codes come with either
+const (
+	mfaMethodTOTP     = "totp"
+	mfaMethodWebAuthn = "webauthn"
+)
+
+// recoveryCodeCount is how many recovery codes a user gets at a time
+const recoveryCodeCount = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// isMFAEnabled reports whether the user has a confirmed TOTP authenticator or a WebAuthn credential
+func (h *AuthHandler) isMFAEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	methods, err := mfaMethods(ctx, h.store.Queries, userID)
+	return len(methods) > 0, err
+}
+
+// mfaMethods lists the second factors the user has set up
+func mfaMethods(ctx context.Context, q *sqlc.Queries, userID uuid.UUID) ([]string, error) {
+	methods := []string{}
+
+	totp, err := q.GetUserTOTP(ctx, userID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil && totp.ConfirmedAt.Valid {
+		methods = append(methods, mfaMethodTOTP)
+	}
+
+	credentials, err := q.CountUserWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credentials > 0 {
+		methods = append(methods, mfaMethodWebAuthn)
+	}
+	return methods, nil
+}
+
+// verifySecondFactor checks a TOTP code or, failing that, a recovery code of a user with MFA.
+// Either can only be used once. Recovery codes only exist while MFA is enabled.
+func (h *AuthHandler) verifySecondFactor(ctx context.Context, userID uuid.UUID, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	totp, err := h.store.GetUserTOTP(ctx, userID)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	if err == nil && totp.ConfirmedAt.Valid {
+		if ok, err := h.verifyTOTP(ctx, totp, code); ok || err != nil {
+			return ok, err
+		}
+	}
+
+	used, err := h.store.UseRecoveryCode(ctx, sqlc.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: utils.HashToken(normalizeRecoveryCode(code)),
+	})
+	if err != nil {
+		return false, err
+	}
+	return used > 0, nil
+}
+
+// verifyTOTP checks a TOTP code and records its time step, so that a code seen by someone
+// else cannot be replayed
+func (h *AuthHandler) verifyTOTP(ctx context.Context, totp sqlc.UserTotp, code string) (bool, error) {
+	secret, err := utils.DecryptSecret(totp.Secret, h.config.JWT.KeyEncryptionKey)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	updated, err := h.store.UseTOTPStep(ctx, sqlc.UseTOTPStepParams{
+		UserID:       totp.UserID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// replaceRecoveryCodes invalidates the recovery codes of a user and generates new ones. Only
+// their hashes are stored, the codes are shown to the user once.
+func replaceRecoveryCodes(ctx context.Context, q *sqlc.Queries, userID uuid.UUID) ([]string, error) {
+	if err := q.DeleteUserRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		if err := q.CreateRecoveryCode(ctx, sqlc.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: utils.HashToken(normalizeRecoveryCode(code)),
+		}); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode generates a 50-bit code formatted as two groups of five characters
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+	return encoded[:5] + "-" + encoded[5:], nil
+}
+
+// normalizeRecoveryCode lets recovery codes be typed in any case, with or without the dash
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
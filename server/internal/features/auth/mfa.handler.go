@@ -0,0 +1,353 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+var errTOTPAlreadyConfirmed = errors.New("totp authenticator has already been confirmed")
+
+// GetMFAStatus tells the authenticated user whether MFA is enabled, with which second factors,
+// and how many recovery codes they have left
+func (h *AuthHandler) GetMFAStatus(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	ctx := c.Request().Context()
+
+	methods, err := mfaMethods(ctx, h.store.Queries, userID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to check multi-factor authentication", err)
+	}
+
+	res := MFAStatusResponse{Enabled: len(methods) > 0, Methods: methods}
+	if res.Enabled {
+		res.RecoveryCodesRemaining, err = h.store.CountUnusedRecoveryCodes(ctx, userID)
+		if err != nil {
+			return utils.RespondWithInternalError(c, "Failed to count recovery codes", err)
+		}
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"MFA status retrieved successfully",
+		res,
+	)
+}
+
+// EnrollTOTP starts setting up a TOTP authenticator for the authenticated user. The secret is
+// returned for the authenticator app and only takes effect once ConfirmTOTP saw a first code.
+func (h *AuthHandler) EnrollTOTP(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	// Parse the request body
+	req := new(EnrollTOTPRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	user, err := h.store.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respondWithUnauthenticated(c)
+		}
+		return utils.RespondWithInternalError(c, "Failed to fetch user", err)
+	}
+
+	if !utils.ComparePasswords(user.PasswordHash, req.CurrentPassword) {
+		return respondWithWrongPassword(c)
+	}
+
+	secret, encodedSecret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to generate TOTP secret", err)
+	}
+	encrypted, err := utils.EncryptSecret(secret, h.config.JWT.KeyEncryptionKey)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to encrypt TOTP secret", err)
+	}
+
+	// A confirmed authenticator has to be disabled before a new one can be set up
+	_, err = h.store.UpsertUserTOTP(ctx, sqlc.UpsertUserTOTPParams{
+		UserID: user.ID,
+		Secret: encrypted,
+	})
+	if err == sql.ErrNoRows {
+		return respondWithMFAAlreadyEnabled(c)
+	}
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to save TOTP secret", err)
+	}
+
+	res := EnrollTOTPResponse{
+		Secret:          encodedSecret,
+		ProvisioningURI: utils.TOTPProvisioningURI(h.config.Auth.MFAIssuer, user.Email, secret),
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Scan the provisioning URI with an authenticator app and confirm with a code",
+		res,
+	)
+}
+
+// ConfirmTOTP enables MFA once the user proves their authenticator app works by entering a
+// first code. The recovery codes are returned, this is the only time they are shown.
+func (h *AuthHandler) ConfirmTOTP(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	// Parse the request body
+	req := new(ConfirmTOTPRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	totp, err := h.store.GetUserTOTP(ctx, userID)
+	if err == sql.ErrNoRows {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"No pending enrollment",
+			utils.ErrorCodeInvalidRequest,
+			"Start the TOTP enrollment first",
+			nil,
+		)
+	}
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch TOTP authenticator", err)
+	}
+	if totp.ConfirmedAt.Valid {
+		return respondWithMFAAlreadyEnabled(c)
+	}
+
+	secret, err := utils.DecryptSecret(totp.Secret, h.config.JWT.KeyEncryptionKey)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to decrypt TOTP secret", err)
+	}
+	step, valid := utils.ValidateTOTP(secret, req.Code, time.Now())
+	if !valid {
+		return respondWithInvalidCode(c)
+	}
+
+	var codes []string
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		confirmed, err := q.ConfirmUserTOTP(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if confirmed == 0 {
+			return errTOTPAlreadyConfirmed
+		}
+		if _, err := q.UseTOTPStep(ctx, sqlc.UseTOTPStepParams{UserID: userID, LastUsedStep: step}); err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(ctx, q, userID)
+		return err
+	})
+	if errors.Is(err, errTOTPAlreadyConfirmed) {
+		return respondWithMFAAlreadyEnabled(c)
+	}
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to enable multi-factor authentication", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Multi-factor authentication enabled, store the recovery codes in a safe place",
+		RecoveryCodesResponse{RecoveryCodes: codes},
+	)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the authenticated user after
+// re-authenticating with the password and a second factor
+func (h *AuthHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	user, err := h.reauthenticateMFA(c)
+	if user == nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	var codes []string
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		codes, err = replaceRecoveryCodes(ctx, q, user.ID)
+		return err
+	})
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to generate recovery codes", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Recovery codes regenerated, the previous codes no longer work",
+		RecoveryCodesResponse{RecoveryCodes: codes},
+	)
+}
+
+// DisableMFA removes the TOTP authenticator, security keys and recovery codes of the
+// authenticated user after re-authenticating with the password and a second factor
+func (h *AuthHandler) DisableMFA(c echo.Context) error {
+	user, err := h.reauthenticateMFA(c)
+	if user == nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.DeleteUserTOTP(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := q.DeleteUserWebAuthnCredentials(ctx, user.ID); err != nil {
+			return err
+		}
+		return q.DeleteUserRecoveryCodes(ctx, user.ID)
+	})
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to disable multi-factor authentication", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Multi-factor authentication disabled",
+		MFAStatusResponse{Enabled: false, Methods: []string{}},
+	)
+}
+
+// reauthenticateMFA checks the password and a second factor of the authenticated user before
+// MFA settings are changed. When no user is returned the error response has already been sent
+// and its result is returned instead.
+func (h *AuthHandler) reauthenticateMFA(c echo.Context) (*sqlc.User, error) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, respondWithUnauthenticated(c)
+	}
+
+	// Parse the request body
+	req := new(MFAReauthRequest)
+	if err := c.Bind(req); err != nil {
+		return nil, utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return nil, err
+	}
+
+	ctx := c.Request().Context()
+
+	user, err := h.store.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, respondWithUnauthenticated(c)
+		}
+		return nil, utils.RespondWithInternalError(c, "Failed to fetch user", err)
+	}
+
+	if !utils.ComparePasswords(user.PasswordHash, req.CurrentPassword) {
+		return nil, respondWithWrongPassword(c)
+	}
+
+	enabled, err := h.isMFAEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, utils.RespondWithInternalError(c, "Failed to check multi-factor authentication", err)
+	}
+	if !enabled {
+		return nil, utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"MFA not enabled",
+			utils.ErrorCodeInvalidRequest,
+			"Multi-factor authentication is not enabled",
+			nil,
+		)
+	}
+
+	valid, err := h.verifySecondFactor(ctx, user.ID, req.Code)
+	if err != nil {
+		return nil, utils.RespondWithInternalError(c, "Failed to verify code", err)
+	}
+	if !valid {
+		return nil, respondWithInvalidCode(c)
+	}
+
+	return &user, nil
+}
+
+func respondWithMFAAlreadyEnabled(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeConflict,
+		"MFA already enabled",
+		utils.ErrorCodeDuplicateEntry,
+		"Multi-factor authentication is already enabled, disable it first to set up a new authenticator",
+		nil,
+	)
+}
+
+func respondWithInvalidCode(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeBadRequest,
+		"Invalid code",
+		utils.ErrorCodeInvalidRequest,
+		"Invalid authentication code",
+		map[string]any{
+			"code": "Invalid authentication code",
+		},
+	)
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// validateNewPassword validates req, which sets a new password for user, including the password
+// rules that depend on the user: personal information and the password history. When false is
+// returned the error response has already been sent, or the validation error is returned for the
+// validation middleware to send.
+func (h *AuthHandler) validateNewPassword(c echo.Context, req any, user sqlc.User) (bool, error) {
+	ctx := c.Request().Context()
+
+	// The current password counts as the latest one in the history
+	var hashes []string
+	if limit := h.config.PasswordPolicy.History; limit > 0 {
+		hashes = append(hashes, user.PasswordHash)
+
+		history, err := h.store.ListUserPasswordHistory(ctx, sqlc.ListUserPasswordHistoryParams{
+			UserID: user.ID,
+			Limit:  int32(limit),
+		})
+		if err != nil {
+			return false, utils.RespondWithInternalError(c, "Failed to fetch password history", err)
+		}
+		for _, hash := range history {
+			if len(hashes) < limit && hash != user.PasswordHash {
+				hashes = append(hashes, hash)
+			}
+		}
+	}
+
+	ctx = utils.WithPasswordContext(ctx, utils.PasswordContext{
+		Email:          user.Email,
+		Name:           user.FullName,
+		PasswordHashes: hashes,
+	})
+	if err := utils.ValidateCtx(c, ctx, req); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// recordPasswordHistory adds the hash of a new password to the history of a user and forgets the
+// hashes that no longer count
+func (h *AuthHandler) recordPasswordHistory(ctx context.Context, q *sqlc.Queries, userID uuid.UUID, passwordHash string) error {
+	limit := h.config.PasswordPolicy.History
+	if limit <= 0 {
+		return nil
+	}
+
+	err := q.CreatePasswordHistory(ctx, sqlc.CreatePasswordHistoryParams{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.DeleteOldPasswordHistory(ctx, sqlc.DeleteOldPasswordHistoryParams{
+		UserID: userID,
+		Keep:   int32(limit),
+	})
+}
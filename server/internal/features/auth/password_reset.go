@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/mailer"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+)
+
+// sendPasswordResetEmail replaces the outstanding password reset links of a user with a new one
+// and emails it to them. Only the hash of the token is stored.
+func (h *AuthHandler) sendPasswordResetEmail(ctx context.Context, user sqlc.User) error {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.InvalidateUserPasswordResetTokens(ctx, user.ID); err != nil {
+			return err
+		}
+		_, err := q.CreatePasswordResetToken(ctx, sqlc.CreatePasswordResetTokenParams{
+			UserID:    user.ID,
+			Token:     utils.HashToken(token),
+			ExpiresAt: time.Now().Add(h.config.Auth.PasswordResetExpiry),
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	link, err := url.Parse(h.config.Auth.PasswordResetURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	msg, err := mailer.NewTemplateMessage(user.Email, mailer.TemplatePasswordReset, mailer.LinkData{
+		Name:      user.FullName,
+		Link:      link.String(),
+		ExpiresIn: h.config.Auth.PasswordResetExpiry,
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.mailer.Send(ctx, msg)
+}
+
+// sendPasswordChangedEmail tells a user their password was changed, so that they notice when
+// someone else did it
+func (h *AuthHandler) sendPasswordChangedEmail(ctx context.Context, user sqlc.User, ipAddress string, sessionsEnded bool) error {
+	msg, err := mailer.NewTemplateMessage(user.Email, mailer.TemplatePasswordChanged, mailer.PasswordChangedData{
+		Name:          user.FullName,
+		Time:          time.Now(),
+		IPAddress:     ipAddress,
+		SessionsEnded: sessionsEnded,
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.mailer.Send(ctx, msg)
+}
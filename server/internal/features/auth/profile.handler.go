@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// GetProfile returns the profile of the authenticated user
+func (h *AuthHandler) GetProfile(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	user, err := h.store.GetUserByID(c.Request().Context(), userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respondWithUnauthenticated(c)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch user",
+			err,
+		)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Profile retrieved successfully",
+		toProfileResponse(user),
+	)
+}
+
+// UpdateProfile changes the full name and date of birth of the authenticated user
+func (h *AuthHandler) UpdateProfile(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	// Parse the request body
+	req := new(UpdateProfileRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	user, err := h.store.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respondWithUnauthenticated(c)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch user",
+			err,
+		)
+	}
+
+	// Fields left out keep their value
+	params := sqlc.UpdateUserProfileParams{
+		ID:          user.ID,
+		FullName:    user.FullName,
+		DateOfBirth: user.DateOfBirth,
+	}
+	if req.FullName != nil {
+		params.FullName = *req.FullName
+	}
+	if req.DateOfBirth != nil {
+		parsedDOB, err := time.Parse("2006-01-02", *req.DateOfBirth)
+		if err != nil {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeBadRequest,
+				"Invalid date format",
+				utils.ErrorCodeInvalidRequest,
+				"Date of birth must be in YYYY-MM-DD format",
+				err,
+			)
+		}
+		if !isOldEnough(parsedDOB) {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeBadRequest,
+				"Age requirement not met",
+				utils.ErrorCodeInvalidRequest,
+				"User must be older than 12 years",
+				map[string]any{
+					"date_of_birth": "User must be older than 12 years",
+				},
+			)
+		}
+		params.DateOfBirth = sql.NullTime{Time: parsedDOB, Valid: true}
+	}
+
+	user, err = h.store.UpdateUserProfile(ctx, params)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not update profile",
+			err,
+		)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Profile updated successfully",
+		toProfileResponse(user),
+	)
+}
+
+func toProfileResponse(user sqlc.User) ProfileResponse {
+	res := ProfileResponse{
+		ID:            user.ID.String(),
+		Email:         user.Email,
+		FullName:      user.FullName,
+		EmailVerified: user.EmailVerified.Valid && user.EmailVerified.Bool,
+		CreatedAt:     user.CreatedAt.Time,
+		UpdatedAt:     user.UpdatedAt.Time,
+	}
+	if user.DateOfBirth.Valid {
+		res.DateOfBirth = user.DateOfBirth.Time.Format("2006-01-02")
+	}
+	return res
+}
+
+func respondWithUnauthenticated(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeUnauthorized,
+		"Unauthorized",
+		utils.ErrorCodeUnauthorized,
+		"User not authenticated",
+		nil,
+	)
+}
+
+func respondWithWrongPassword(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeBadRequest,
+		"Incorrect password",
+		utils.ErrorCodeInvalidRequest,
+		"The current password is incorrect",
+		map[string]any{
+			"current_password": "The current password is incorrect",
+		},
+	)
+}
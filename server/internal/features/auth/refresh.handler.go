@@ -72,12 +72,29 @@ func (h *AuthHandler) RefreshToken(c echo.Context) error {
 		)
 	}
 
+	// Roles are looked up again, so that changes apply from the next refresh
+	roles, permissions, err := h.rolesAndPermissions(c.Request().Context(), user.ID)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal Server Error",
+			utils.ErrorCodeDatabaseError,
+			"Failed to retrieve roles",
+			err,
+		)
+	}
+
 	// Create new access token claims
 	claims := utils.AccessTokenClaims{
 		UserID:        user.ID.String(),
 		Email:         user.Email,
 		FullName:      user.FullName,
 		EmailVerified: user.EmailVerified.Valid && user.EmailVerified.Bool,
+		SessionID:     session.ID.String(),
+		AMR:           session.Amr,
+		Roles:         roles,
+		Permissions:   permissions,
 	}
 
 	// Create the new access token
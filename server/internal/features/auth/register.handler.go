@@ -2,6 +2,7 @@ package auth
 
 import (
 	"database/sql"
+	"log"
 	"time"
 
 	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
@@ -23,8 +24,12 @@ func (h *AuthHandler) Register(c echo.Context) error {
 		)
 	}
 
-	// Validate the request body
-	if err := c.Validate(req); err != nil {
+	// Validate the request body, the password must not contain the email address or name
+	ctx := utils.WithPasswordContext(c.Request().Context(), utils.PasswordContext{
+		Email: req.Email,
+		Name:  req.FullName,
+	})
+	if err := utils.ValidateCtx(c, ctx, req); err != nil {
 		return err
 	}
 
@@ -79,12 +84,7 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	}
 
 	// Validate age (must be greater than 12)
-	now := time.Now()
-	age := now.Year() - parsedDOB.Year()
-	if now.YearDay() < parsedDOB.YearDay() {
-		age--
-	}
-	if age <= 12 {
+	if !isOldEnough(parsedDOB) {
 		return utils.RespondWithError(
 			c,
 			utils.StatusCodeBadRequest,
@@ -122,6 +122,16 @@ func (h *AuthHandler) Register(c echo.Context) error {
 		)
 	}
 
+	// Start the password history with the first password
+	if err := h.recordPasswordHistory(c.Request().Context(), h.store.Queries, user.ID, user.PasswordHash); err != nil {
+		log.Printf("Failed to record password history of user %s: %v", user.ID, err)
+	}
+
+	// Send the verification email, the account is created either way and the link can be resent
+	if err := h.sendVerificationEmail(c.Request().Context(), user, user.Email); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+	}
+
 	// Create the response
 	res := RegisterResponse{
 		UserID: user.ID.String(),
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// ResendVerificationEmail sends a new verification link. The response is the same whether or
+// not the address belongs to an unverified account, and throttled requests are dropped silently
+// for the same reason.
+func (h *AuthHandler) ResendVerificationEmail(c echo.Context) error {
+	// Parse the request body
+	req := new(ResendVerificationRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	res := ResendVerificationResponse{
+		Success: true,
+		Message: "If the address belongs to an unverified account, a new verification link has been sent",
+	}
+
+	user, err := h.store.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, res.Message, res)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch user",
+			err,
+		)
+	}
+
+	if user.EmailVerified.Valid && user.EmailVerified.Bool {
+		return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, res.Message, res)
+	}
+
+	// Only one verification email is sent per resend interval
+	latest, err := h.store.GetLatestEmailVerificationToken(ctx, user.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch verification token",
+			err,
+		)
+	}
+	if err == nil && latest.CreatedAt.Valid && time.Since(latest.CreatedAt.Time) < h.config.Auth.EmailVerificationResendInterval {
+		return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, res.Message, res)
+	}
+
+	// The response is the same whether or not the address has an account
+	sendInBackground(ctx, fmt.Sprintf("Failed to send verification email to user %s", user.ID), func(ctx context.Context) error {
+		return h.sendVerificationEmail(ctx, user, user.Email)
+	})
+
+	return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, res.Message, res)
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var errResetTokenUsed = errors.New("password reset token has already been used")
+
+// ResetPassword sets a new password with a password reset link. Every session of the user is
+// logged out afterwards, as whoever knew the old password may still be using one.
+func (h *AuthHandler) ResetPassword(c echo.Context) error {
+	// Parse the request body
+	req := new(ResetPasswordRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	// Tokens are stored hashed
+	token, err := h.store.GetPasswordResetTokenByToken(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeBadRequest,
+				"Invalid reset link",
+				utils.ErrorCodeInvalidRequest,
+				"The password reset link is invalid",
+				nil,
+			)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch password reset token",
+			err,
+		)
+	}
+
+	if token.UsedAt.Valid {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid reset link",
+			utils.ErrorCodeInvalidRequest,
+			"The password reset link has already been used or was replaced by a newer one",
+			nil,
+		)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Reset link expired",
+			utils.ErrorCodeTokenExpired,
+			"The password reset link has expired, please request a new one",
+			nil,
+		)
+	}
+
+	user, err := h.store.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch user",
+			err,
+		)
+	}
+
+	// The new password must not contain personal information or reuse a previous one
+	if ok, err := h.validateNewPassword(c, req, user); !ok {
+		return err
+	}
+
+	// Hash the new password
+	hashedPassword, err := utils.Hash(req.Password)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeInternalError,
+			"Could not hash password",
+			err,
+		)
+	}
+
+	// Use up the token and every other outstanding link along with setting the password
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		used, err := q.MarkPasswordResetTokenUsed(ctx, token.ID)
+		if err != nil {
+			return err
+		}
+		if used == 0 {
+			return errResetTokenUsed
+		}
+		if err := q.UpdateUserPassword(ctx, sqlc.UpdateUserPasswordParams{
+			ID:           token.UserID,
+			PasswordHash: hashedPassword,
+		}); err != nil {
+			return err
+		}
+		if err := h.recordPasswordHistory(ctx, q, token.UserID, hashedPassword); err != nil {
+			return err
+		}
+		return q.InvalidateUserPasswordResetTokens(ctx, token.UserID)
+	})
+	if err != nil {
+		if errors.Is(err, errResetTokenUsed) {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeBadRequest,
+				"Invalid reset link",
+				utils.ErrorCodeInvalidRequest,
+				"The password reset link has already been used",
+				nil,
+			)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not reset password",
+			err,
+		)
+	}
+
+	// Log the user out everywhere, including the applications the sessions were used with
+	sessionsRevoked, err := h.endUserSessions(ctx, token.UserID, uuid.Nil)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Failed to revoke sessions",
+			err,
+		)
+	}
+
+	// Let the user know, the password is reset either way. Whoever got the link owns the email
+	// address, so a lockout of the account is lifted as well.
+	if err := h.clearAccountThrottle(ctx, user.Email); err != nil {
+		log.Printf("Failed to clear failed logins of user %s: %v", user.ID, err)
+	}
+	if err := h.sendPasswordChangedEmail(ctx, user, c.RealIP(), true); err != nil {
+		log.Printf("Failed to send password changed email to user %s: %v", user.ID, err)
+	}
+
+	// Create the response
+	res := ResetPasswordResponse{
+		Success:         true,
+		SessionsRevoked: sessionsRevoked,
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Password reset successfully",
+		res,
+	)
+}
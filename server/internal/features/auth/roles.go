@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+)
+
+// rolesAndPermissions returns the roles of a user and the permissions they grant, for the
+// access tokens of their sessions
+func (h *AuthHandler) rolesAndPermissions(ctx context.Context, userID uuid.UUID) ([]string, []string, error) {
+	roles, err := h.store.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	permissions, err := h.store.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return roles, permissions, nil
+}
+
+// grantAdminRole gives the admin role to a user who verified the configured admin email
+func (h *AuthHandler) grantAdminRole(ctx context.Context, q *sqlc.Queries, userID uuid.UUID, verifiedEmail string) error {
+	if h.config.AdminEmail == "" || !strings.EqualFold(verifiedEmail, h.config.AdminEmail) {
+		return nil
+	}
+	return q.AssignUserRole(ctx, sqlc.AssignUserRoleParams{
+		UserID:   userID,
+		RoleName: utils.RoleAdmin,
+	})
+}
+
+// BootstrapAdmin gives the admin role to the user with the configured admin email on startup.
+// Nothing happens until that user has registered and verified the address, verifying it then
+// grants the role.
+func BootstrapAdmin(ctx context.Context, store *db.Store, adminEmail string) error {
+	if adminEmail == "" {
+		return nil
+	}
+
+	user, err := store.GetUserByEmail(ctx, adminEmail)
+	if err == sql.ErrNoRows {
+		log.Printf("Admin %s has not registered yet, the admin role is granted once the address is verified", adminEmail)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !(user.EmailVerified.Valid && user.EmailVerified.Bool) {
+		log.Printf("Admin %s has not verified the address yet, the admin role is granted once it is", adminEmail)
+		return nil
+	}
+
+	return store.AssignUserRole(ctx, sqlc.AssignUserRoleParams{
+		UserID:   user.ID,
+		RoleName: utils.RoleAdmin,
+	})
+}
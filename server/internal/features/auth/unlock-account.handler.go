@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// UnlockAccount lifts the lockout of an account with the link from the account locked email
+func (h *AuthHandler) UnlockAccount(c echo.Context) error {
+	// Parse the request body
+	req := new(UnlockAccountRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	// Tokens are stored hashed
+	token, err := h.store.GetAccountUnlockTokenByToken(ctx, utils.HashToken(req.Token))
+	if err != nil && err != sql.ErrNoRows {
+		return utils.RespondWithInternalError(c, "Failed to fetch unlock token", err)
+	}
+	if err == sql.ErrNoRows || token.UsedAt.Valid {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid unlock link",
+			utils.ErrorCodeInvalidRequest,
+			"The unlock link is invalid or has already been used",
+			nil,
+		)
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Unlock link expired",
+			utils.ErrorCodeTokenExpired,
+			"The unlock link has expired, the account unlocks by itself after a while",
+			nil,
+		)
+	}
+
+	used, err := h.store.MarkAccountUnlockTokenUsed(ctx, token.ID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to use unlock token", err)
+	}
+	if used == 0 {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid unlock link",
+			utils.ErrorCodeInvalidRequest,
+			"The unlock link is invalid or has already been used",
+			nil,
+		)
+	}
+
+	user, err := h.store.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch user", err)
+	}
+	if err := h.clearAccountThrottle(ctx, user.Email); err != nil {
+		return utils.RespondWithInternalError(c, "Failed to unlock account", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Account unlocked, you can log in again",
+		UnlockAccountResponse{Success: true, UserID: user.ID.String()},
+	)
+}
+
+// AdminUnlockUser lets an admin lift the lockout of an account
+func (h *AuthHandler) AdminUnlockUser(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid user ID",
+			utils.ErrorCodeInvalidRequest,
+			"User ID must be a valid UUID",
+			nil,
+		)
+	}
+
+	ctx := c.Request().Context()
+
+	user, err := h.store.GetUserByID(ctx, userID)
+	if err == sql.ErrNoRows {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeNotFound,
+			"User not found",
+			utils.ErrorCodeResourceNotFound,
+			"No user with this ID exists",
+			nil,
+		)
+	}
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch user", err)
+	}
+
+	if err := h.clearAccountThrottle(ctx, user.Email); err != nil {
+		return utils.RespondWithInternalError(c, "Failed to unlock account", err)
+	}
+	// The links sent for the lockout are no longer needed
+	if err := h.store.InvalidateUserAccountUnlockTokens(ctx, user.ID); err != nil {
+		return utils.RespondWithInternalError(c, "Failed to invalidate unlock links", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Account unlocked",
+		UnlockAccountResponse{Success: true, UserID: user.ID.String()},
+	)
+}
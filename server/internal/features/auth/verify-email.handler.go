@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	errVerificationTokenUsed = errors.New("verification token has already been used")
+	errEmailTaken            = errors.New("email address belongs to another account")
+)
+
+func (h *AuthHandler) VerifyEmail(c echo.Context) error {
+	// Parse the request body
+	req := new(VerifyEmailRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	// Tokens are stored hashed
+	token, err := h.store.GetEmailVerificationTokenByToken(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeBadRequest,
+				"Invalid verification link",
+				utils.ErrorCodeInvalidRequest,
+				"The verification link is invalid",
+				nil,
+			)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not fetch verification token",
+			err,
+		)
+	}
+
+	if token.UsedAt.Valid {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid verification link",
+			utils.ErrorCodeInvalidRequest,
+			"The verification link has already been used or was replaced by a newer one",
+			nil,
+		)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Verification link expired",
+			utils.ErrorCodeTokenExpired,
+			"The verification link has expired, please request a new one",
+			nil,
+		)
+	}
+
+	// Use up the token and make the address it was sent to the user's verified email, which
+	// completes an email change
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		return h.confirmEmailVerification(ctx, q, token)
+	})
+	if err != nil {
+		if errors.Is(err, errVerificationTokenUsed) {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeBadRequest,
+				"Invalid verification link",
+				utils.ErrorCodeInvalidRequest,
+				"The verification link is no longer valid",
+				nil,
+			)
+		}
+		if errors.Is(err, errEmailTaken) {
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeConflict,
+				"Email already in use",
+				utils.ErrorCodeDuplicateEntry,
+				"The email address now belongs to another account",
+				nil,
+			)
+		}
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeInternalError,
+			"Internal server error",
+			utils.ErrorCodeDatabaseError,
+			"Could not verify email address",
+			err,
+		)
+	}
+
+	// Create the response
+	res := VerifyEmailResponse{
+		Email:         token.Email,
+		EmailVerified: true,
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Email verified successfully",
+		res,
+	)
+}
+
+// confirmEmailVerification marks a verification token used and the address it was sent to the
+// verified email of the user, only one concurrent confirmation can win
+func (h *AuthHandler) confirmEmailVerification(ctx context.Context, q *sqlc.Queries, token sqlc.EmailVerificationToken) error {
+	used, err := q.MarkEmailVerificationTokenUsed(ctx, token.ID)
+	if err != nil {
+		return err
+	}
+	if used == 0 {
+		return errVerificationTokenUsed
+	}
+
+	// The new address of an email change may have been registered in the meantime
+	owner, err := q.GetUserByEmail(ctx, token.Email)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && owner.ID != token.UserID {
+		return errEmailTaken
+	}
+
+	_, err = q.ConfirmUserEmail(ctx, sqlc.ConfirmUserEmailParams{
+		ID:    token.UserID,
+		Email: token.Email,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The configured admin gets the admin role once the address is proven to be theirs
+	return h.grantAdminRole(ctx, q, token.UserID, token.Email)
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/webauthn"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// WebAuthn ceremonies a challenge is issued for, a challenge only answers its own ceremony
+const (
+	webauthnCeremonyRegistration = "registration" // Adding a credential to the authenticated user
+	webauthnCeremonyMFA          = "mfa"          // Second login step after the password
+	webauthnCeremonyPasskey      = "passkey"      // Passwordless login with a discoverable credential
+)
+
+var (
+	errInvalidWebAuthnChallenge  = errors.New("webauthn challenge is invalid or expired")
+	errInvalidWebAuthnCredential = errors.New("webauthn credential could not be verified")
+)
+
+// createWebAuthnChallenge starts a ceremony for a user, or for whoever answers it when userID is
+// empty. Only the hash of the challenge is stored.
+func (h *AuthHandler) createWebAuthnChallenge(ctx context.Context, userID uuid.NullUUID, ceremony string) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = h.store.CreateWebAuthnChallenge(ctx, sqlc.CreateWebAuthnChallengeParams{
+		UserID:    userID,
+		Ceremony:  ceremony,
+		Challenge: utils.HashToken(challenge),
+		ExpiresAt: time.Now().Add(h.config.WebAuthn.Timeout),
+	})
+	if err != nil {
+		return "", err
+	}
+	return challenge, nil
+}
+
+// consumeWebAuthnChallenge finds the ceremony a response answers and marks its challenge used,
+// so that a response cannot be replayed. The challenge is returned for verifying the response.
+func (h *AuthHandler) consumeWebAuthnChallenge(ctx context.Context, clientDataJSON string, userID uuid.NullUUID, ceremony string) (string, error) {
+	challenge, err := webauthn.ChallengeOf(clientDataJSON)
+	if err != nil {
+		return "", errInvalidWebAuthnChallenge
+	}
+
+	stored, err := h.store.GetWebAuthnChallenge(ctx, utils.HashToken(challenge))
+	if err == sql.ErrNoRows {
+		return "", errInvalidWebAuthnChallenge
+	}
+	if err != nil {
+		return "", err
+	}
+	if stored.Ceremony != ceremony || stored.UserID != userID || stored.UsedAt.Valid || time.Now().After(stored.ExpiresAt) {
+		return "", errInvalidWebAuthnChallenge
+	}
+
+	used, err := h.store.MarkWebAuthnChallengeUsed(ctx, stored.ID)
+	if err != nil {
+		return "", err
+	}
+	if used == 0 {
+		return "", errInvalidWebAuthnChallenge
+	}
+	return challenge, nil
+}
+
+// verifyWebAuthnAssertion checks the response to an authentication ceremony and records the use
+// of the credential. For the second login step userID is the user logging in and only their
+// credentials are accepted. For passkey logins it is empty and the credential names the user.
+func (h *AuthHandler) verifyWebAuthnAssertion(ctx context.Context, res webauthn.AssertionResponse, userID uuid.NullUUID, ceremony string) (*sqlc.WebauthnCredential, error) {
+	challenge, err := h.consumeWebAuthnChallenge(ctx, res.Response.ClientDataJSON, userID, ceremony)
+	if err != nil {
+		return nil, err
+	}
+
+	rawID, err := webauthn.DecodeID(res.RawID)
+	if err != nil {
+		return nil, errInvalidWebAuthnCredential
+	}
+	credential, err := h.store.GetWebAuthnCredentialByCredentialID(ctx, rawID)
+	if err == sql.ErrNoRows {
+		return nil, errInvalidWebAuthnCredential
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userID.Valid && credential.UserID != userID.UUID {
+		return nil, errInvalidWebAuthnCredential
+	}
+
+	// Discoverable credentials return the user they were created for, which has to be the owner
+	userHandle, err := webauthn.DecodeID(res.Response.UserHandle)
+	if err != nil || (len(userHandle) > 0 && !bytes.Equal(userHandle, credential.UserID[:])) {
+		return nil, errInvalidWebAuthnCredential
+	}
+	if !userID.Valid && len(userHandle) == 0 {
+		return nil, errInvalidWebAuthnCredential
+	}
+
+	// Passkeys replace the password, so the authenticator has to verify the user itself
+	requireUserVerification := ceremony == webauthnCeremonyPasskey
+	assertion, err := h.config.WebAuthn.VerifyAssertion(res, challenge, credential.PublicKey, uint32(credential.SignCount), requireUserVerification)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidWebAuthnCredential, err)
+	}
+
+	err = h.store.UpdateWebAuthnCredentialUsage(ctx, sqlc.UpdateWebAuthnCredentialUsageParams{
+		ID:        credential.ID,
+		SignCount: int64(assertion.SignCount),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// credentialDescriptors lists credentials for the allow or exclude list of a ceremony
+func credentialDescriptors(credentials []sqlc.WebauthnCredential) []webauthn.CredentialDescriptor {
+	descriptors := make([]webauthn.CredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, webauthn.CredentialDescriptor{
+			Type:       "public-key",
+			ID:         webauthn.EncodeID(credential.CredentialID),
+			Transports: credential.Transports,
+		})
+	}
+	return descriptors
+}
+
+// respondWithWebAuthnError answers a ceremony that failed with err
+func respondWithWebAuthnError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errInvalidWebAuthnChallenge):
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request",
+			utils.ErrorCodeInvalidRequest,
+			"The security key request has expired, please try again",
+			nil,
+		)
+	case errors.Is(err, errInvalidWebAuthnCredential):
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeUnauthorized,
+			"Unauthorized",
+			utils.ErrorCodeUnauthorized,
+			"The security key could not be verified",
+			nil,
+		)
+	default:
+		return utils.RespondWithInternalError(c, "Failed to verify security key", err)
+	}
+}
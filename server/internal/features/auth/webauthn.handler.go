@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/webauthn"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var errWebAuthnCredentialNotFound = errors.New("webauthn credential not found")
+
+// ListWebAuthnCredentials lists the security keys and passkeys of the authenticated user
+func (h *AuthHandler) ListWebAuthnCredentials(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	credentials, err := h.store.ListUserWebAuthnCredentials(c.Request().Context(), userID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch security keys", err)
+	}
+
+	res := make([]WebAuthnCredentialResponse, 0, len(credentials))
+	for _, credential := range credentials {
+		res = append(res, toWebAuthnCredentialResponse(credential))
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Security keys retrieved successfully",
+		res,
+	)
+}
+
+// BeginWebAuthnRegistration starts registering a security key or passkey for the authenticated
+// user after re-authenticating with the password, and a second factor once MFA is enabled. The
+// options are passed to navigator.credentials.create() and the result to
+// FinishWebAuthnRegistration.
+func (h *AuthHandler) BeginWebAuthnRegistration(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	// Parse the request body
+	req := new(BeginWebAuthnRegistrationRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	user, err := h.store.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respondWithUnauthenticated(c)
+		}
+		return utils.RespondWithInternalError(c, "Failed to fetch user", err)
+	}
+
+	if !utils.ComparePasswords(user.PasswordHash, req.CurrentPassword) {
+		return respondWithWrongPassword(c)
+	}
+
+	// Once MFA is enabled a new second factor needs one of the existing ones, like changing
+	// any other MFA setting
+	enabled, err := h.isMFAEnabled(ctx, user.ID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to check multi-factor authentication", err)
+	}
+	if enabled {
+		valid, err := h.verifySecondFactor(ctx, user.ID, req.Code)
+		if err != nil {
+			return utils.RespondWithInternalError(c, "Failed to verify code", err)
+		}
+		if !valid {
+			return respondWithInvalidCode(c)
+		}
+	}
+
+	credentials, err := h.store.ListUserWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch security keys", err)
+	}
+
+	challenge, err := h.createWebAuthnChallenge(ctx, uuid.NullUUID{UUID: user.ID, Valid: true}, webauthnCeremonyRegistration)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to create WebAuthn challenge", err)
+	}
+
+	// The user handle is the user ID, passkey logins use it to find the user
+	res := h.config.WebAuthn.CreationOptions(challenge, webauthn.UserEntity{
+		ID:          webauthn.EncodeID(user.ID[:]),
+		Name:        user.Email,
+		DisplayName: user.FullName,
+	}, credentialDescriptors(credentials))
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Create the credential with the security key or passkey provider",
+		res,
+	)
+}
+
+// FinishWebAuthnRegistration stores the credential created for BeginWebAuthnRegistration. The
+// first credential of a user without MFA enables it, the recovery codes are then returned, this
+// is the only time they are shown.
+func (h *AuthHandler) FinishWebAuthnRegistration(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	// Parse the request body
+	req := new(FinishWebAuthnRegistrationRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	// Validate the request body
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	challenge, err := h.consumeWebAuthnChallenge(ctx, req.Credential.Response.ClientDataJSON, uuid.NullUUID{UUID: userID, Valid: true}, webauthnCeremonyRegistration)
+	if err != nil {
+		return respondWithWebAuthnError(c, err)
+	}
+
+	created, err := h.config.WebAuthn.VerifyRegistration(req.Credential, challenge)
+	if err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid credential",
+			utils.ErrorCodeInvalidRequest,
+			"The security key could not be registered",
+			nil,
+		)
+	}
+
+	// An authenticator can only be registered once
+	_, err = h.store.GetWebAuthnCredentialByCredentialID(ctx, created.ID)
+	if err == nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeConflict,
+			"Security key already registered",
+			utils.ErrorCodeDuplicateEntry,
+			"This security key is already registered",
+			nil,
+		)
+	} else if err != sql.ErrNoRows {
+		return utils.RespondWithInternalError(c, "Failed to check security key", err)
+	}
+
+	var res FinishWebAuthnRegistrationResponse
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		methods, err := mfaMethods(ctx, q, userID)
+		if err != nil {
+			return err
+		}
+
+		credential, err := q.CreateWebAuthnCredential(ctx, sqlc.CreateWebAuthnCredentialParams{
+			UserID:       userID,
+			CredentialID: created.ID,
+			PublicKey:    created.PublicKey,
+			SignCount:    int64(created.SignCount),
+			Transports:   created.Transports,
+			Aaguid:       created.AAGUID,
+			Name:         req.Name,
+		})
+		if err != nil {
+			return err
+		}
+		res.Credential = toWebAuthnCredentialResponse(credential)
+
+		if len(methods) == 0 {
+			res.RecoveryCodes, err = replaceRecoveryCodes(ctx, q, userID)
+		}
+		return err
+	})
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to save security key", err)
+	}
+
+	message := "Security key registered"
+	if len(res.RecoveryCodes) > 0 {
+		message = "Security key registered and multi-factor authentication enabled, store the recovery codes in a safe place"
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeCreated,
+		message,
+		res,
+	)
+}
+
+// DeleteWebAuthnCredential removes a security key or passkey of the authenticated user after
+// re-authenticating with the password and a second factor. Removing the last second factor
+// disables MFA and invalidates the recovery codes.
+func (h *AuthHandler) DeleteWebAuthnCredential(c echo.Context) error {
+	credentialID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return respondWithWebAuthnCredentialNotFound(c)
+	}
+
+	user, err := h.reauthenticateMFA(c)
+	if user == nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		deleted, err := q.DeleteWebAuthnCredential(ctx, sqlc.DeleteWebAuthnCredentialParams{
+			ID:     credentialID,
+			UserID: user.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return errWebAuthnCredentialNotFound
+		}
+
+		methods, err := mfaMethods(ctx, q, user.ID)
+		if err != nil {
+			return err
+		}
+		if len(methods) == 0 {
+			return q.DeleteUserRecoveryCodes(ctx, user.ID)
+		}
+		return nil
+	})
+	if errors.Is(err, errWebAuthnCredentialNotFound) {
+		return respondWithWebAuthnCredentialNotFound(c)
+	}
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to remove security key", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Security key removed",
+		nil,
+	)
+}
+
+func toWebAuthnCredentialResponse(credential sqlc.WebauthnCredential) WebAuthnCredentialResponse {
+	res := WebAuthnCredentialResponse{
+		ID:         credential.ID.String(),
+		Name:       credential.Name,
+		Transports: credential.Transports,
+		CreatedAt:  credential.CreatedAt.Time,
+	}
+	if credential.LastUsedAt.Valid {
+		res.LastUsedAt = &credential.LastUsedAt.Time
+	}
+	return res
+}
+
+func respondWithWebAuthnCredentialNotFound(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeNotFound,
+		"Security key not found",
+		utils.ErrorCodeResourceNotFound,
+		"No security key with this ID is registered",
+		nil,
+	)
+}
@@ -1,8 +1,13 @@
 package client
 
 import (
+	"database/sql"
+	"encoding/json"
+	"strings"
 	"time"
 
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
@@ -13,62 +18,79 @@ import (
 
 // === Create Client Dto ===
 type CreateClientRequest struct {
-	Name           string   `json:"name" validate:"required,min=3,max=100"`
-	Description    string   `json:"description" validate:"max=500"`
-	RedirectURIs   []string `json:"redirect_uris" validate:"required,min=1,dive,url"`
-	WebsiteURL     string   `json:"website_url" validate:"omitempty,url,max=255"`
-	IsConfidential bool     `json:"is_confidential"`
+	Name                             string          `json:"name" validate:"required,min=3,max=100"`
+	Description                      string          `json:"description" validate:"max=500"`
+	RedirectURIs                     []string        `json:"redirect_uris" validate:"omitempty,dive,redirect_uri"`
+	WebsiteURL                       string          `json:"website_url" validate:"omitempty,url,max=255"`
+	IsConfidential                   bool            `json:"is_confidential"`
+	GrantTypes                       []string        `json:"grant_types" validate:"omitempty,dive,oneof=authorization_code refresh_token client_credentials urn:ietf:params:oauth:grant-type:device_code"`
+	AllowedScopes                    []string        `json:"allowed_scopes" validate:"omitempty,dive,min=1,max=100"`
+	IsFirstParty                     bool            `json:"is_first_party"`
+	TokenEndpointAuthMethod          string          `json:"token_endpoint_auth_method" validate:"omitempty,oneof=client_secret_basic client_secret_post client_secret_jwt private_key_jwt none"`
+	LogoURI                          string          `json:"logo_uri" validate:"omitempty,url,max=255"`
+	JWKS                             json.RawMessage `json:"jwks"`
+	JWKSURI                          string          `json:"jwks_uri" validate:"omitempty,url,max=255"`
+	PostLogoutRedirectURIs           []string        `json:"post_logout_redirect_uris" validate:"omitempty,dive,redirect_uri"`
+	BackchannelLogoutURI             string          `json:"backchannel_logout_uri" validate:"omitempty,url,max=255"`
+	BackchannelLogoutSessionRequired bool            `json:"backchannel_logout_session_required"`
+}
+
+// ClientFields are the details of a client every client response includes
+type ClientFields struct {
+	ID                               uuid.UUID       `json:"id"`
+	Name                             string          `json:"name"`
+	Description                      string          `json:"description"`
+	ClientID                         string          `json:"client_id"`
+	RedirectURIs                     []string        `json:"redirect_uris"`
+	WebsiteURL                       string          `json:"website_url"`
+	IsActive                         bool            `json:"is_active"`
+	IsConfidential                   bool            `json:"is_confidential"`
+	GrantTypes                       []string        `json:"grant_types"`
+	AllowedScopes                    []string        `json:"allowed_scopes"`
+	IsFirstParty                     bool            `json:"is_first_party"`
+	TokenEndpointAuthMethod          string          `json:"token_endpoint_auth_method"`
+	LogoURI                          string          `json:"logo_uri"`
+	JWKS                             json.RawMessage `json:"jwks,omitempty"`
+	JWKSURI                          string          `json:"jwks_uri,omitempty"`
+	PostLogoutRedirectURIs           []string        `json:"post_logout_redirect_uris"`
+	BackchannelLogoutURI             string          `json:"backchannel_logout_uri,omitempty"`
+	BackchannelLogoutSessionRequired bool            `json:"backchannel_logout_session_required"`
+	CreatedAt                        time.Time       `json:"created_at"`
+	UpdatedAt                        time.Time       `json:"updated_at"`
 }
 
 type CreateClientResponse struct {
-	ID             uuid.UUID `json:"id"`
-	Name           string    `json:"name"`
-	Description    string    `json:"description"`
-	ClientID       string    `json:"client_id"`
-	ClientSecret   string    `json:"client_secret"`
-	RedirectURIs   []string  `json:"redirect_uris"`
-	WebsiteURL     string    `json:"website_url"`
-	IsActive       bool      `json:"is_active"`
-	IsConfidential bool      `json:"is_confidential"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ClientFields
+	ClientSecret string `json:"client_secret"`
 }
 
 // === Get Client Dto ===
 type ClientResponse struct {
-	ID             uuid.UUID `json:"id"`
-	Name           string    `json:"name"`
-	Description    string    `json:"description"`
-	ClientID       string    `json:"client_id"`
-	RedirectURIs   []string  `json:"redirect_uris"`
-	WebsiteURL     string    `json:"website_url"`
-	IsActive       bool      `json:"is_active"`
-	IsConfidential bool      `json:"is_confidential"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ClientFields
 }
 
 type ClientDetailResponse struct {
-	ID             uuid.UUID `json:"id"`
-	Name           string    `json:"name"`
-	Description    string    `json:"description"`
-	ClientID       string    `json:"client_id"`
-	ClientSecret   string    `json:"client_secret"`
-	RedirectURIs   []string  `json:"redirect_uris"`
-	WebsiteURL     string    `json:"website_url"`
-	IsActive       bool      `json:"is_active"`
-	IsConfidential bool      `json:"is_confidential"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ClientFields
+	ClientSecret string `json:"client_secret"`
 }
 
 // === Update Client Dto ===
 type UpdateClientRequest struct {
-	Name           string   `json:"name" validate:"required,min=3,max=100"`
-	Description    string   `json:"description" validate:"max=500"`
-	RedirectURIs   []string `json:"redirect_uris" validate:"required,min=1,dive,url"`
-	WebsiteURL     string   `json:"website_url" validate:"omitempty,url,max=255"`
-	IsConfidential bool     `json:"is_confidential"`
+	Name                             string          `json:"name" validate:"required,min=3,max=100"`
+	Description                      string          `json:"description" validate:"max=500"`
+	RedirectURIs                     []string        `json:"redirect_uris" validate:"omitempty,dive,redirect_uri"`
+	WebsiteURL                       string          `json:"website_url" validate:"omitempty,url,max=255"`
+	IsConfidential                   bool            `json:"is_confidential"`
+	GrantTypes                       []string        `json:"grant_types" validate:"omitempty,dive,oneof=authorization_code refresh_token client_credentials urn:ietf:params:oauth:grant-type:device_code"`
+	AllowedScopes                    []string        `json:"allowed_scopes" validate:"omitempty,dive,min=1,max=100"`
+	IsFirstParty                     bool            `json:"is_first_party"`
+	TokenEndpointAuthMethod          string          `json:"token_endpoint_auth_method" validate:"omitempty,oneof=client_secret_basic client_secret_post client_secret_jwt private_key_jwt none"`
+	LogoURI                          string          `json:"logo_uri" validate:"omitempty,url,max=255"`
+	JWKS                             json.RawMessage `json:"jwks"`
+	JWKSURI                          string          `json:"jwks_uri" validate:"omitempty,url,max=255"`
+	PostLogoutRedirectURIs           []string        `json:"post_logout_redirect_uris" validate:"omitempty,dive,redirect_uri"`
+	BackchannelLogoutURI             string          `json:"backchannel_logout_uri" validate:"omitempty,url,max=255"`
+	BackchannelLogoutSessionRequired bool            `json:"backchannel_logout_session_required"`
 }
 
 // === List Clients Dto ===
@@ -79,17 +101,154 @@ type ListClientsResponse struct {
 
 // === Regenerate Secret Dto ===
 type RegenerateSecretResponse struct {
-	ID             uuid.UUID `json:"id"`
-	Name           string    `json:"name"`
-	Description    string    `json:"description"`
-	ClientID       string    `json:"client_id"`
-	ClientSecret   string    `json:"client_secret"`
-	RedirectURIs   []string  `json:"redirect_uris"`
-	WebsiteURL     string    `json:"website_url"`
-	IsActive       bool      `json:"is_active"`
-	IsConfidential bool      `json:"is_confidential"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ClientFields
+	ClientSecret string `json:"client_secret"`
+}
+
+// === Client Registration Dto ===
+// Client metadata of dynamic client registration (RFC 7591 section 2). Updates must repeat the
+// client_id and may include the client_secret (RFC 7592 section 2.2).
+type ClientRegistrationRequest struct {
+	RedirectURIs                     []string        `json:"redirect_uris"`
+	TokenEndpointAuthMethod          string          `json:"token_endpoint_auth_method"`
+	GrantTypes                       []string        `json:"grant_types"`
+	ResponseTypes                    []string        `json:"response_types"`
+	ClientName                       string          `json:"client_name"`
+	ClientURI                        string          `json:"client_uri"`
+	LogoURI                          string          `json:"logo_uri"`
+	Scope                            string          `json:"scope"`
+	JWKS                             json.RawMessage `json:"jwks"`
+	JWKSURI                          string          `json:"jwks_uri"`
+	PostLogoutRedirectURIs           []string        `json:"post_logout_redirect_uris"`
+	BackchannelLogoutURI             string          `json:"backchannel_logout_uri"`
+	BackchannelLogoutSessionRequired bool            `json:"backchannel_logout_session_required"`
+	ClientID                         string          `json:"client_id"`
+	ClientSecret                     string          `json:"client_secret"`
+}
+
+type ClientRegistrationResponse struct {
+	ClientID                         string          `json:"client_id"`
+	ClientSecret                     string          `json:"client_secret,omitempty"`
+	ClientIDIssuedAt                 int64           `json:"client_id_issued_at"`
+	ClientSecretExpiresAt            int64           `json:"client_secret_expires_at"`
+	RegistrationAccessToken          string          `json:"registration_access_token,omitempty"`
+	RegistrationClientURI            string          `json:"registration_client_uri"`
+	RedirectURIs                     []string        `json:"redirect_uris"`
+	TokenEndpointAuthMethod          string          `json:"token_endpoint_auth_method"`
+	GrantTypes                       []string        `json:"grant_types"`
+	ResponseTypes                    []string        `json:"response_types"`
+	ClientName                       string          `json:"client_name"`
+	ClientURI                        string          `json:"client_uri,omitempty"`
+	LogoURI                          string          `json:"logo_uri,omitempty"`
+	Scope                            string          `json:"scope,omitempty"`
+	JWKS                             json.RawMessage `json:"jwks,omitempty"`
+	JWKSURI                          string          `json:"jwks_uri,omitempty"`
+	PostLogoutRedirectURIs           []string        `json:"post_logout_redirect_uris"`
+	BackchannelLogoutURI             string          `json:"backchannel_logout_uri,omitempty"`
+	BackchannelLogoutSessionRequired bool            `json:"backchannel_logout_session_required"`
+}
+
+// SupportedGrantTypes are the grant types a client can be registered for
+var SupportedGrantTypes = []string{
+	"authorization_code",
+	"refresh_token",
+	"client_credentials",
+	"urn:ietf:params:oauth:grant-type:device_code",
+}
+
+// DefaultGrantTypes are given to clients that are created or updated without grant types
+var DefaultGrantTypes = []string{"authorization_code", "refresh_token"}
+
+// GrantTypesOrDefault returns the requested grant types, falling back to DefaultGrantTypes
+func GrantTypesOrDefault(grantTypes []string) []string {
+	if len(grantTypes) == 0 {
+		return DefaultGrantTypes
+	}
+	return grantTypes
+}
+
+// Token endpoint authentication methods (RFC 7591 section 2)
+const (
+	TokenEndpointAuthMethodClientSecretBasic = "client_secret_basic"
+	TokenEndpointAuthMethodClientSecretPost  = "client_secret_post"
+	TokenEndpointAuthMethodClientSecretJWT   = "client_secret_jwt"
+	TokenEndpointAuthMethodPrivateKeyJWT     = "private_key_jwt"
+	TokenEndpointAuthMethodNone              = "none"
+)
+
+// TokenEndpointAuthMethodOrDefault returns the requested authentication method, falling back to
+// client_secret_basic for confidential clients and none for public ones
+func TokenEndpointAuthMethodOrDefault(method string, isConfidential bool) string {
+	if method != "" {
+		return method
+	}
+	if isConfidential {
+		return TokenEndpointAuthMethodClientSecretBasic
+	}
+	return TokenEndpointAuthMethodNone
+}
+
+// NormalizeClientKeys checks the public keys registered for a client against its authentication
+// method. private_key_jwt clients need either a JWKS or a jwks_uri, the JWKS is returned with
+// only its public key members. Problems are returned as a description.
+func NormalizeClientKeys(authMethod string, jwks json.RawMessage, jwksURI string) (string, string) {
+	hasJWKS := len(jwks) > 0 && string(jwks) != "null"
+	if hasJWKS && jwksURI != "" {
+		return "", "jwks and jwks_uri cannot both be registered"
+	}
+	if authMethod == TokenEndpointAuthMethodPrivateKeyJWT && !hasJWKS && jwksURI == "" {
+		return "", "private_key_jwt requires jwks or jwks_uri"
+	}
+	if jwksURI != "" && !strings.HasPrefix(jwksURI, "https://") {
+		return "", "jwks_uri must be an https URL"
+	}
+	if !hasJWKS {
+		return "", ""
+	}
+
+	parsed, err := utils.ParseJWKS(jwks)
+	if err != nil {
+		return "", err.Error()
+	}
+	normalized, err := json.Marshal(parsed)
+	if err != nil {
+		return "", err.Error()
+	}
+	return string(normalized), ""
+}
+
+// toClientFields maps a stored client to the details every client response includes
+func toClientFields(client sqlc.Client) ClientFields {
+	return ClientFields{
+		ID:                               client.ID,
+		Name:                             client.Name,
+		Description:                      client.Description.String,
+		ClientID:                         client.ClientID,
+		RedirectURIs:                     StringArrayToSlice(client.RedirectUris),
+		WebsiteURL:                       client.WebsiteUrl.String,
+		IsActive:                         client.IsActive.Bool,
+		IsConfidential:                   client.IsConfidential.Bool,
+		GrantTypes:                       StringArrayToSlice(client.GrantTypes),
+		AllowedScopes:                    StringArrayToSlice(client.AllowedScopes),
+		IsFirstParty:                     client.IsFirstParty,
+		TokenEndpointAuthMethod:          client.TokenEndpointAuthMethod,
+		LogoURI:                          client.LogoUri.String,
+		JWKS:                             jwksToRaw(client.Jwks),
+		JWKSURI:                          client.JwksUri.String,
+		PostLogoutRedirectURIs:           StringArrayToSlice(client.PostLogoutRedirectUris),
+		BackchannelLogoutURI:             client.BackchannelLogoutUri.String,
+		BackchannelLogoutSessionRequired: client.BackchannelLogoutSessionRequired,
+		CreatedAt:                        client.CreatedAt.Time,
+		UpdatedAt:                        client.UpdatedAt.Time,
+	}
+}
+
+// jwksToRaw converts a stored JWKS back into raw JSON for responses
+func jwksToRaw(jwks sql.NullString) json.RawMessage {
+	if !jwks.Valid {
+		return nil
+	}
+	return json.RawMessage(jwks.String)
 }
 
 // Helper function to convert pq.StringArray to []string
@@ -10,7 +10,6 @@ import (
 	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"github.com/lib/pq"
 )
 
 // CreateClient handles client creation
@@ -33,6 +32,20 @@ func (h *ClientHandler) CreateClient(c echo.Context) error {
 		return err
 	}
 
+	grantTypes := GrantTypesOrDefault(req.GrantTypes)
+	authMethod := TokenEndpointAuthMethodOrDefault(req.TokenEndpointAuthMethod, req.IsConfidential)
+	jwks, metadataErr := validateClientMetadata(clientMetadataRequest{
+		IsConfidential:          req.IsConfidential,
+		TokenEndpointAuthMethod: authMethod,
+		GrantTypes:              grantTypes,
+		RedirectURIs:            req.RedirectURIs,
+		JWKS:                    req.JWKS,
+		JWKSURI:                 req.JWKSURI,
+	})
+	if metadataErr != nil {
+		return metadataErr.respond(c)
+	}
+
 	// Generate client ID and secret
 	clientID, err := generateClientID()
 	if err != nil {
@@ -48,15 +61,25 @@ func (h *ClientHandler) CreateClient(c echo.Context) error {
 
 	// Create the client
 	client, err := h.store.CreateClient(c.Request().Context(), sqlc.CreateClientParams{
-		Name:           req.Name,
-		Description:    sql.NullString{String: req.Description, Valid: req.Description != ""},
-		ClientID:       clientID,
-		ClientSecret:   clientSecret,
-		RedirectUris:   pq.StringArray(req.RedirectURIs),
-		WebsiteUrl:     sql.NullString{String: req.WebsiteURL, Valid: req.WebsiteURL != ""},
-		IsActive:       sql.NullBool{Bool: true, Valid: true},
-		IsConfidential: sql.NullBool{Bool: req.IsConfidential, Valid: true},
-		CreatedBy:      uuid.NullUUID{}, // Empty for now
+		Name:                             req.Name,
+		Description:                      sql.NullString{String: req.Description, Valid: req.Description != ""},
+		ClientID:                         clientID,
+		ClientSecret:                     clientSecret,
+		RedirectUris:                     SliceToStringArray(req.RedirectURIs),
+		WebsiteUrl:                       sql.NullString{String: req.WebsiteURL, Valid: req.WebsiteURL != ""},
+		IsActive:                         sql.NullBool{Bool: true, Valid: true},
+		IsConfidential:                   sql.NullBool{Bool: req.IsConfidential, Valid: true},
+		GrantTypes:                       SliceToStringArray(grantTypes),
+		AllowedScopes:                    SliceToStringArray(req.AllowedScopes),
+		IsFirstParty:                     req.IsFirstParty,
+		TokenEndpointAuthMethod:          authMethod,
+		LogoUri:                          sql.NullString{String: req.LogoURI, Valid: req.LogoURI != ""},
+		Jwks:                             sql.NullString{String: jwks, Valid: jwks != ""},
+		JwksUri:                          sql.NullString{String: req.JWKSURI, Valid: req.JWKSURI != ""},
+		PostLogoutRedirectUris:           SliceToStringArray(req.PostLogoutRedirectURIs),
+		BackchannelLogoutUri:             sql.NullString{String: req.BackchannelLogoutURI, Valid: req.BackchannelLogoutURI != ""},
+		BackchannelLogoutSessionRequired: req.BackchannelLogoutSessionRequired,
+		CreatedBy:                        uuid.NullUUID{}, // Empty for now
 	})
 	if err != nil {
 		return utils.RespondWithError(
@@ -71,17 +94,8 @@ func (h *ClientHandler) CreateClient(c echo.Context) error {
 
 	// Create the response
 	res := CreateClientResponse{
-		ID:             client.ID,
-		Name:           client.Name,
-		Description:    client.Description.String,
-		ClientID:       client.ClientID,
-		ClientSecret:   client.ClientSecret,
-		RedirectURIs:   StringArrayToSlice(client.RedirectUris),
-		WebsiteURL:     client.WebsiteUrl.String,
-		IsActive:       client.IsActive.Bool,
-		IsConfidential: client.IsConfidential.Bool,
-		CreatedAt:      client.CreatedAt.Time,
-		UpdatedAt:      client.UpdatedAt.Time,
+		ClientFields: toClientFields(client),
+		ClientSecret: client.ClientSecret,
 	}
 
 	// Send the response
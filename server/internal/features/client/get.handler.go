@@ -49,16 +49,7 @@ func (h *ClientHandler) GetClientById(c echo.Context) error {
 
 	// Create the response
 	res := ClientResponse{
-		ID:             client.ID,
-		Name:           client.Name,
-		Description:    client.Description.String,
-		ClientID:       client.ClientID,
-		RedirectURIs:   StringArrayToSlice(client.RedirectUris),
-		WebsiteURL:     client.WebsiteUrl.String,
-		IsActive:       client.IsActive.Bool,
-		IsConfidential: client.IsConfidential.Bool,
-		CreatedAt:      client.CreatedAt.Time,
-		UpdatedAt:      client.UpdatedAt.Time,
+		ClientFields: toClientFields(client),
 	}
 
 	// Send the response
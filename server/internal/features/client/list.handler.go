@@ -37,16 +37,7 @@ func (h *ClientHandler) GetAllClients(c echo.Context) error {
 	clientResponses := make([]ClientResponse, len(clients))
 	for i, client := range clients {
 		clientResponses[i] = ClientResponse{
-			ID:             client.ID,
-			Name:           client.Name,
-			Description:    client.Description.String,
-			ClientID:       client.ClientID,
-			RedirectURIs:   StringArrayToSlice(client.RedirectUris),
-			WebsiteURL:     client.WebsiteUrl.String,
-			IsActive:       client.IsActive.Bool,
-			IsConfidential: client.IsConfidential.Bool,
-			CreatedAt:      client.CreatedAt.Time,
-			UpdatedAt:      client.UpdatedAt.Time,
+			ClientFields: toClientFields(client),
 		}
 	}
 
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// clientMetadataRequest is the client metadata checked alike for clients managed by an
+// administrator and clients registered dynamically, with the defaults already applied
+type clientMetadataRequest struct {
+	IsConfidential          bool
+	TokenEndpointAuthMethod string
+	GrantTypes              []string
+	RedirectURIs            []string
+	JWKS                    json.RawMessage
+	JWKSURI                 string
+}
+
+// clientMetadataError is client metadata rejected by validateClientMetadata
+type clientMetadataError struct {
+	Field       string
+	Title       string
+	Description string
+	Detail      string
+}
+
+func (e *clientMetadataError) respond(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeBadRequest,
+		e.Title,
+		utils.ErrorCodeInvalidRequest,
+		e.Description,
+		map[string]any{
+			e.Field: e.Detail,
+		},
+	)
+}
+
+// validateClientMetadata checks that the client type, grant types, authentication method, keys
+// and redirect URIs of a client fit together. The JWKS is returned with only its public key
+// members.
+func validateClientMetadata(req clientMetadataRequest) (string, *clientMetadataError) {
+	// Machine-to-machine clients authenticate with their secret, so they must be confidential
+	if slices.Contains(req.GrantTypes, "client_credentials") && !req.IsConfidential {
+		return "", &clientMetadataError{
+			Field:       "grant_types",
+			Title:       "Invalid grant types",
+			Description: "The client_credentials grant requires a confidential client",
+			Detail:      "client_credentials requires is_confidential to be true",
+		}
+	}
+
+	// Public clients have no secret to authenticate with and confidential clients must use one
+	if (req.TokenEndpointAuthMethod == TokenEndpointAuthMethodNone) == req.IsConfidential {
+		return "", &clientMetadataError{
+			Field:       "token_endpoint_auth_method",
+			Title:       "Invalid token endpoint auth method",
+			Description: "The token endpoint auth method does not match the client type",
+			Detail:      "none is required for public clients and not allowed for confidential clients",
+		}
+	}
+
+	// Clients authenticating with private_key_jwt must register their public keys
+	jwks, problem := NormalizeClientKeys(req.TokenEndpointAuthMethod, req.JWKS, req.JWKSURI)
+	if problem != "" {
+		return "", &clientMetadataError{
+			Field:       "jwks",
+			Title:       "Invalid client keys",
+			Description: problem,
+			Detail:      problem,
+		}
+	}
+
+	// Only the authorization code grant sends the user back to the client, devices and
+	// machine-to-machine clients have nowhere to be redirected to
+	if slices.Contains(req.GrantTypes, "authorization_code") && len(req.RedirectURIs) == 0 {
+		return "", &clientMetadataError{
+			Field:       "redirect_uris",
+			Title:       "Invalid redirect URIs",
+			Description: "The authorization_code grant requires at least one redirect URI",
+			Detail:      "at least one redirect URI is required for authorization_code",
+		}
+	}
+
+	return jwks, nil
+}
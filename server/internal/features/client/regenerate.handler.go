@@ -57,17 +57,8 @@ func (h *ClientHandler) RegenerateClientSecret(c echo.Context) error {
 
 	// Convert to response DTO
 	response := RegenerateSecretResponse{
-		ID:             updatedClient.ID,
-		ClientID:       updatedClient.ClientID,
-		ClientSecret:   updatedClient.ClientSecret,
-		Name:           updatedClient.Name,
-		Description:    updatedClient.Description.String,
-		RedirectURIs:   StringArrayToSlice(updatedClient.RedirectUris),
-		WebsiteURL:     updatedClient.WebsiteUrl.String,
-		IsActive:       updatedClient.IsActive.Bool,
-		IsConfidential: updatedClient.IsConfidential.Bool,
-		CreatedAt:      updatedClient.CreatedAt.Time,
-		UpdatedAt:      updatedClient.UpdatedAt.Time,
+		ClientFields: toClientFields(updatedClient),
+		ClientSecret: updatedClient.ClientSecret,
 	}
 
 	return utils.RespondWithSuccess(
@@ -127,17 +118,8 @@ func (h *ClientHandler) RegenerateClientSecretByClientID(c echo.Context) error {
 
 	// Convert to response DTO
 	response := RegenerateSecretResponse{
-		ID:             updatedClient.ID,
-		ClientID:       updatedClient.ClientID,
-		ClientSecret:   updatedClient.ClientSecret,
-		Name:           updatedClient.Name,
-		Description:    updatedClient.Description.String,
-		RedirectURIs:   StringArrayToSlice(updatedClient.RedirectUris),
-		WebsiteURL:     updatedClient.WebsiteUrl.String,
-		IsActive:       updatedClient.IsActive.Bool,
-		IsConfidential: updatedClient.IsConfidential.Bool,
-		CreatedAt:      updatedClient.CreatedAt.Time,
-		UpdatedAt:      updatedClient.UpdatedAt.Time,
+		ClientFields: toClientFields(updatedClient),
+		ClientSecret: updatedClient.ClientSecret,
 	}
 
 	return utils.RespondWithSuccess(
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"log"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterClient handles dynamic client registration (RFC 7591). Registration is limited to
+// callers presenting the configured initial access token, the new client receives a
+// registration access token to manage itself with.
+func (h *ClientHandler) RegisterClient(c echo.Context) error {
+	if h.config.OAuth.RegistrationToken == "" {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeForbidden, utils.OAuthErrorAccessDenied, "Dynamic client registration is disabled")
+	}
+	token := bearerToken(c)
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.config.OAuth.RegistrationToken)) != 1 {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidToken, "Invalid initial access token")
+	}
+
+	req := new(ClientRegistrationRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidClientMetadata, "Could not parse client metadata")
+	}
+
+	ctx := c.Request().Context()
+
+	metadata, regErr, err := h.validateRegistration(ctx, req)
+	if err != nil {
+		return respondWithRegistrationServerError(c, "Failed to validate client metadata", err)
+	}
+	if regErr != nil {
+		return regErr.respond(c)
+	}
+
+	clientID, err := generateClientID()
+	if err != nil {
+		return respondWithRegistrationServerError(c, "Failed to generate client ID", err)
+	}
+	clientSecret, err := generateClientSecret()
+	if err != nil {
+		return respondWithRegistrationServerError(c, "Failed to generate client secret", err)
+	}
+	registrationToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return respondWithRegistrationServerError(c, "Failed to generate registration access token", err)
+	}
+
+	name := metadata.Name
+	if name == "" {
+		name = clientID
+	}
+
+	// The client and its registration access token are stored together
+	var client sqlc.Client
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		client, err = q.CreateClient(ctx, sqlc.CreateClientParams{
+			Name:                             name,
+			ClientID:                         clientID,
+			ClientSecret:                     clientSecret,
+			RedirectUris:                     SliceToStringArray(metadata.RedirectURIs),
+			WebsiteUrl:                       sql.NullString{String: metadata.WebsiteURL, Valid: metadata.WebsiteURL != ""},
+			IsActive:                         sql.NullBool{Bool: true, Valid: true},
+			IsConfidential:                   sql.NullBool{Bool: metadata.IsConfidential, Valid: true},
+			GrantTypes:                       SliceToStringArray(metadata.GrantTypes),
+			AllowedScopes:                    SliceToStringArray(metadata.AllowedScopes),
+			TokenEndpointAuthMethod:          metadata.TokenEndpointAuthMethod,
+			LogoUri:                          sql.NullString{String: metadata.LogoURI, Valid: metadata.LogoURI != ""},
+			Jwks:                             sql.NullString{String: metadata.JWKS, Valid: metadata.JWKS != ""},
+			JwksUri:                          sql.NullString{String: metadata.JWKSURI, Valid: metadata.JWKSURI != ""},
+			PostLogoutRedirectUris:           SliceToStringArray(metadata.PostLogoutRedirectURIs),
+			BackchannelLogoutUri:             sql.NullString{String: metadata.BackchannelLogoutURI, Valid: metadata.BackchannelLogoutURI != ""},
+			BackchannelLogoutSessionRequired: metadata.BackchannelLogoutSessionRequired,
+		})
+		if err != nil {
+			return err
+		}
+
+		client.RegistrationAccessToken = sql.NullString{String: utils.HashToken(registrationToken), Valid: true}
+		return q.SetClientRegistrationAccessToken(ctx, sqlc.SetClientRegistrationAccessTokenParams{
+			ID:                      client.ID,
+			RegistrationAccessToken: client.RegistrationAccessToken,
+		})
+	})
+	if err != nil {
+		return respondWithRegistrationServerError(c, "Failed to register client", err)
+	}
+
+	res := h.toRegistrationResponse(client)
+	res.RegistrationAccessToken = registrationToken
+	return respondWithRegistration(c, utils.StatusCodeCreated, res)
+}
+
+// respondWithRegistrationServerError logs the underlying error and hides it from the client
+func respondWithRegistrationServerError(c echo.Context, message string, err error) error {
+	log.Printf("OAUTH SERVER ERROR: %v - %v", message, err)
+	return utils.RespondWithOAuthError(c, utils.StatusCodeInternalError, utils.OAuthErrorServerError, "An unexpected error occurred while processing your request")
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// registrationError is a rejected registration request, reported in the OAuth error format
+type registrationError struct {
+	status      utils.StatusCode
+	code        utils.OAuthErrorCode
+	description string
+}
+
+func newRegistrationError(code utils.OAuthErrorCode, description string) *registrationError {
+	return &registrationError{status: utils.StatusCodeBadRequest, code: code, description: description}
+}
+
+func (e *registrationError) respond(c echo.Context) error {
+	return utils.RespondWithOAuthError(c, e.status, e.code, e.description)
+}
+
+// supportedAuthMethods are the token endpoint authentication methods a client can register
+var supportedAuthMethods = []string{
+	TokenEndpointAuthMethodClientSecretBasic,
+	TokenEndpointAuthMethodClientSecretPost,
+	TokenEndpointAuthMethodClientSecretJWT,
+	TokenEndpointAuthMethodPrivateKeyJWT,
+	TokenEndpointAuthMethodNone,
+}
+
+// clientMetadata is registered client metadata that passed validation
+type clientMetadata struct {
+	Name                             string
+	RedirectURIs                     []string
+	WebsiteURL                       string
+	LogoURI                          string
+	IsConfidential                   bool
+	TokenEndpointAuthMethod          string
+	JWKS                             string
+	JWKSURI                          string
+	GrantTypes                       []string
+	AllowedScopes                    []string
+	PostLogoutRedirectURIs           []string
+	BackchannelLogoutURI             string
+	BackchannelLogoutSessionRequired bool
+}
+
+// validateRegistration checks registered client metadata and applies the defaults of
+// RFC 7591 section 2. Database failures are returned as err.
+func (h *ClientHandler) validateRegistration(ctx context.Context, req *ClientRegistrationRequest) (*clientMetadata, *registrationError, error) {
+	authMethod := req.TokenEndpointAuthMethod
+	if authMethod == "" {
+		authMethod = TokenEndpointAuthMethodClientSecretBasic
+	}
+	if !slices.Contains(supportedAuthMethods, authMethod) {
+		return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "Unsupported token_endpoint_auth_method"), nil
+	}
+	isConfidential := authMethod != TokenEndpointAuthMethodNone
+
+	if len(req.JWKSURI) > 255 {
+		return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "jwks_uri must be at most 255 characters"), nil
+	}
+
+	grantTypes := req.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{"authorization_code"}
+	}
+	for _, grantType := range grantTypes {
+		if !slices.Contains(SupportedGrantTypes, grantType) {
+			return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "Unsupported grant type "+grantType), nil
+		}
+	}
+
+	// The code response type and the authorization code grant go together (RFC 7591 section 2.1)
+	usesCode := slices.Contains(grantTypes, "authorization_code")
+	for _, responseType := range req.ResponseTypes {
+		if responseType != "code" {
+			return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "Only the code response type is supported"), nil
+		}
+	}
+	if len(req.ResponseTypes) > 0 && !usesCode {
+		return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "The code response type requires the authorization_code grant"), nil
+	}
+
+	jwks, metadataErr := validateClientMetadata(clientMetadataRequest{
+		IsConfidential:          isConfidential,
+		TokenEndpointAuthMethod: authMethod,
+		GrantTypes:              grantTypes,
+		RedirectURIs:            req.RedirectURIs,
+		JWKS:                    req.JWKS,
+		JWKSURI:                 req.JWKSURI,
+	})
+	if metadataErr != nil {
+		code := utils.OAuthErrorInvalidClientMetadata
+		if metadataErr.Field == "redirect_uris" {
+			code = utils.OAuthErrorInvalidRedirectURI
+		}
+		return nil, newRegistrationError(code, metadataErr.Description), nil
+	}
+
+	for _, redirectURI := range req.RedirectURIs {
+		if !utils.IsValidRedirectURI(redirectURI) {
+			return nil, newRegistrationError(utils.OAuthErrorInvalidRedirectURI, "Redirect URIs must be https URLs, http URLs of the loopback interface or use a reverse domain name scheme, without a fragment"), nil
+		}
+	}
+
+	// Logout URIs are matched exactly like redirect URIs (OpenID Connect RP-Initiated Logout 1.0
+	// section 3.1 and Back-Channel Logout 1.0 section 2.2)
+	for _, redirectURI := range req.PostLogoutRedirectURIs {
+		if !utils.IsValidRedirectURI(redirectURI) {
+			return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "Post logout redirect URIs must be https URLs, http URLs of the loopback interface or use a reverse domain name scheme, without a fragment"), nil
+		}
+	}
+	if !isValidWebURL(req.BackchannelLogoutURI) || strings.Contains(req.BackchannelLogoutURI, "#") {
+		return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "backchannel_logout_uri must be an http or https URL without a fragment"), nil
+	}
+
+	if len(req.ClientName) > 100 {
+		return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "client_name must be at most 100 characters"), nil
+	}
+	if !isValidWebURL(req.ClientURI) {
+		return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "client_uri must be an http or https URL"), nil
+	}
+	if !isValidWebURL(req.LogoURI) {
+		return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "logo_uri must be an http or https URL"), nil
+	}
+
+	// Every scope the client may request must be registered
+	scopes := []string{}
+	for _, scope := range strings.Fields(req.Scope) {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) > 0 {
+		registered, err := h.store.GetScopesByNames(ctx, scopes)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(registered) != len(scopes) {
+			return nil, newRegistrationError(utils.OAuthErrorInvalidClientMetadata, "The scope contains an unknown scope"), nil
+		}
+	}
+
+	return &clientMetadata{
+		Name:                             req.ClientName,
+		RedirectURIs:                     req.RedirectURIs,
+		WebsiteURL:                       req.ClientURI,
+		LogoURI:                          req.LogoURI,
+		IsConfidential:                   isConfidential,
+		TokenEndpointAuthMethod:          authMethod,
+		JWKS:                             jwks,
+		JWKSURI:                          req.JWKSURI,
+		GrantTypes:                       grantTypes,
+		AllowedScopes:                    scopes,
+		PostLogoutRedirectURIs:           req.PostLogoutRedirectURIs,
+		BackchannelLogoutURI:             req.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired: req.BackchannelLogoutSessionRequired,
+	}, nil, nil
+}
+
+// authenticateRegistration looks up the client of a client configuration request by its
+// registration access token (RFC 7592 section 3). Unknown clients and invalid tokens are
+// rejected alike. When nil is returned the error response has already been sent and its
+// result is returned instead.
+func (h *ClientHandler) authenticateRegistration(c echo.Context) (*sqlc.Client, error) {
+	token := bearerToken(c)
+
+	client, err := h.store.GetClientByClientId(c.Request().Context(), c.Param("client_id"))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, respondWithRegistrationServerError(c, "Failed to fetch client", err)
+	}
+
+	valid := err == nil && token != "" && client.RegistrationAccessToken.Valid &&
+		subtle.ConstantTimeCompare([]byte(utils.HashToken(token)), []byte(client.RegistrationAccessToken.String)) == 1
+	if !valid {
+		return nil, utils.RespondWithOAuthError(c, utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidToken, "Invalid registration access token")
+	}
+	return &client, nil
+}
+
+// toRegistrationResponse describes a registered client in the format of RFC 7591 section 3.2.1
+func (h *ClientHandler) toRegistrationResponse(client sqlc.Client) ClientRegistrationResponse {
+	isConfidential := !client.IsConfidential.Valid || client.IsConfidential.Bool
+
+	res := ClientRegistrationResponse{
+		ClientID:                         client.ClientID,
+		ClientIDIssuedAt:                 client.CreatedAt.Time.Unix(),
+		RegistrationClientURI:            h.config.JWT.Issuer + "/oauth/register/" + client.ClientID,
+		RedirectURIs:                     StringArrayToSlice(client.RedirectUris),
+		TokenEndpointAuthMethod:          client.TokenEndpointAuthMethod,
+		GrantTypes:                       StringArrayToSlice(client.GrantTypes),
+		ResponseTypes:                    []string{},
+		ClientName:                       client.Name,
+		ClientURI:                        client.WebsiteUrl.String,
+		LogoURI:                          client.LogoUri.String,
+		Scope:                            strings.Join(client.AllowedScopes, " "),
+		JWKS:                             jwksToRaw(client.Jwks),
+		JWKSURI:                          client.JwksUri.String,
+		PostLogoutRedirectURIs:           StringArrayToSlice(client.PostLogoutRedirectUris),
+		BackchannelLogoutURI:             client.BackchannelLogoutUri.String,
+		BackchannelLogoutSessionRequired: client.BackchannelLogoutSessionRequired,
+	}
+	// The secret is only of use to clients authenticating with it
+	if isConfidential && client.TokenEndpointAuthMethod != TokenEndpointAuthMethodPrivateKeyJWT {
+		res.ClientSecret = client.ClientSecret
+	}
+	if slices.Contains(res.GrantTypes, "authorization_code") {
+		res.ResponseTypes = []string{"code"}
+	}
+	return res
+}
+
+// isValidWebURL reports whether an optional URL is an absolute http or https URL
+func isValidWebURL(value string) bool {
+	if value == "" {
+		return true
+	}
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && len(value) <= 255
+}
+
+// bearerToken returns the bearer token of the Authorization header
+func bearerToken(c echo.Context) string {
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return auth[7:]
+	}
+	return ""
+}
+
+// respondWithRegistration sends a client registration response, which must never be cached
+func respondWithRegistration(c echo.Context, status utils.StatusCode, res ClientRegistrationResponse) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	c.Response().Header().Set("Pragma", "no-cache")
+	return c.JSON(int(status), res)
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// GetRegistration handles reading a dynamically registered client (RFC 7592 section 2.1)
+func (h *ClientHandler) GetRegistration(c echo.Context) error {
+	client, err := h.authenticateRegistration(c)
+	if client == nil {
+		return err
+	}
+
+	return respondWithRegistration(c, utils.StatusCodeSuccess, h.toRegistrationResponse(*client))
+}
+
+// UpdateRegistration handles replacing the metadata of a dynamically registered client
+// (RFC 7592 section 2.2). Omitted metadata falls back to its default.
+func (h *ClientHandler) UpdateRegistration(c echo.Context) error {
+	client, err := h.authenticateRegistration(c)
+	if client == nil {
+		return err
+	}
+
+	req := new(ClientRegistrationRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidClientMetadata, "Could not parse client metadata")
+	}
+
+	if req.ClientID != client.ClientID {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidRequest, "client_id does not match the registered client")
+	}
+	if req.ClientSecret != "" && subtle.ConstantTimeCompare([]byte(req.ClientSecret), []byte(client.ClientSecret)) != 1 {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidRequest, "client_secret does not match the registered client")
+	}
+
+	ctx := c.Request().Context()
+
+	metadata, regErr, err := h.validateRegistration(ctx, req)
+	if err != nil {
+		return respondWithRegistrationServerError(c, "Failed to validate client metadata", err)
+	}
+	if regErr != nil {
+		return regErr.respond(c)
+	}
+
+	name := metadata.Name
+	if name == "" {
+		name = client.ClientID
+	}
+
+	// The description and first-party flag are managed by administrators only
+	_, err = h.store.UpdateClient(ctx, sqlc.UpdateClientParams{
+		ID:                               client.ID,
+		Name:                             name,
+		Description:                      client.Description,
+		RedirectUris:                     SliceToStringArray(metadata.RedirectURIs),
+		WebsiteUrl:                       sql.NullString{String: metadata.WebsiteURL, Valid: metadata.WebsiteURL != ""},
+		IsConfidential:                   sql.NullBool{Bool: metadata.IsConfidential, Valid: true},
+		GrantTypes:                       SliceToStringArray(metadata.GrantTypes),
+		AllowedScopes:                    SliceToStringArray(metadata.AllowedScopes),
+		IsFirstParty:                     client.IsFirstParty,
+		TokenEndpointAuthMethod:          metadata.TokenEndpointAuthMethod,
+		LogoUri:                          sql.NullString{String: metadata.LogoURI, Valid: metadata.LogoURI != ""},
+		Jwks:                             sql.NullString{String: metadata.JWKS, Valid: metadata.JWKS != ""},
+		JwksUri:                          sql.NullString{String: metadata.JWKSURI, Valid: metadata.JWKSURI != ""},
+		PostLogoutRedirectUris:           SliceToStringArray(metadata.PostLogoutRedirectURIs),
+		BackchannelLogoutUri:             sql.NullString{String: metadata.BackchannelLogoutURI, Valid: metadata.BackchannelLogoutURI != ""},
+		BackchannelLogoutSessionRequired: metadata.BackchannelLogoutSessionRequired,
+	})
+	if err != nil {
+		return respondWithRegistrationServerError(c, "Failed to update client", err)
+	}
+
+	updated, err := h.store.GetClientByClientId(ctx, client.ClientID)
+	if err != nil {
+		return respondWithRegistrationServerError(c, "Failed to fetch client", err)
+	}
+
+	return respondWithRegistration(c, utils.StatusCodeSuccess, h.toRegistrationResponse(updated))
+}
+
+// DeleteRegistration handles deprovisioning a dynamically registered client (RFC 7592 section 2.3)
+func (h *ClientHandler) DeleteRegistration(c echo.Context) error {
+	client, err := h.authenticateRegistration(c)
+	if client == nil {
+		return err
+	}
+
+	if err := h.store.DeleteClient(c.Request().Context(), client.ID); err != nil {
+		return respondWithRegistrationServerError(c, "Failed to delete client", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"github.com/lib/pq"
 )
 
 // UpdateClient handles updating a client
@@ -44,14 +43,38 @@ func (h *ClientHandler) UpdateClient(c echo.Context) error {
 		return err
 	}
 
+	grantTypes := GrantTypesOrDefault(req.GrantTypes)
+	authMethod := TokenEndpointAuthMethodOrDefault(req.TokenEndpointAuthMethod, req.IsConfidential)
+	jwks, metadataErr := validateClientMetadata(clientMetadataRequest{
+		IsConfidential:          req.IsConfidential,
+		TokenEndpointAuthMethod: authMethod,
+		GrantTypes:              grantTypes,
+		RedirectURIs:            req.RedirectURIs,
+		JWKS:                    req.JWKS,
+		JWKSURI:                 req.JWKSURI,
+	})
+	if metadataErr != nil {
+		return metadataErr.respond(c)
+	}
+
 	// Update the client
 	client, err := h.store.UpdateClient(c.Request().Context(), sqlc.UpdateClientParams{
-		ID:             clientID,
-		Name:           req.Name,
-		Description:    sql.NullString{String: req.Description, Valid: req.Description != ""},
-		RedirectUris:   pq.StringArray(req.RedirectURIs),
-		WebsiteUrl:     sql.NullString{String: req.WebsiteURL, Valid: req.WebsiteURL != ""},
-		IsConfidential: sql.NullBool{Bool: req.IsConfidential, Valid: true},
+		ID:                               clientID,
+		Name:                             req.Name,
+		Description:                      sql.NullString{String: req.Description, Valid: req.Description != ""},
+		RedirectUris:                     SliceToStringArray(req.RedirectURIs),
+		WebsiteUrl:                       sql.NullString{String: req.WebsiteURL, Valid: req.WebsiteURL != ""},
+		IsConfidential:                   sql.NullBool{Bool: req.IsConfidential, Valid: true},
+		GrantTypes:                       SliceToStringArray(grantTypes),
+		AllowedScopes:                    SliceToStringArray(req.AllowedScopes),
+		IsFirstParty:                     req.IsFirstParty,
+		TokenEndpointAuthMethod:          authMethod,
+		LogoUri:                          sql.NullString{String: req.LogoURI, Valid: req.LogoURI != ""},
+		Jwks:                             sql.NullString{String: jwks, Valid: jwks != ""},
+		JwksUri:                          sql.NullString{String: req.JWKSURI, Valid: req.JWKSURI != ""},
+		PostLogoutRedirectUris:           SliceToStringArray(req.PostLogoutRedirectURIs),
+		BackchannelLogoutUri:             sql.NullString{String: req.BackchannelLogoutURI, Valid: req.BackchannelLogoutURI != ""},
+		BackchannelLogoutSessionRequired: req.BackchannelLogoutSessionRequired,
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -76,16 +99,7 @@ func (h *ClientHandler) UpdateClient(c echo.Context) error {
 
 	// Create the response
 	res := ClientResponse{
-		ID:             client.ID,
-		Name:           client.Name,
-		Description:    client.Description.String,
-		ClientID:       client.ClientID,
-		RedirectURIs:   StringArrayToSlice(client.RedirectUris),
-		WebsiteURL:     client.WebsiteUrl.String,
-		IsActive:       client.IsActive.Bool,
-		IsConfidential: client.IsConfidential.Bool,
-		CreatedAt:      client.CreatedAt.Time,
-		UpdatedAt:      client.UpdatedAt.Time,
+		ClientFields: toClientFields(client),
 	}
 
 	// Send the response
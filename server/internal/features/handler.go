@@ -3,9 +3,11 @@ package features
 import (
 	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/mailer"
 )
 
 type AppHandlers struct {
-	Store *db.Store
-	Cfg   *config.Config
+	Store  *db.Store
+	Cfg    *config.Config
+	Mailer mailer.Mailer
 }
@@ -0,0 +1,138 @@
+package oauth
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var errAuthorizationCodeUsed = errors.New("authorization code has already been used")
+
+// authorizationCodeGrant exchanges an authorization code for tokens (RFC 6749 section 4.1.3)
+func (h *OAuthHandler) authorizationCodeGrant(c echo.Context, req *TokenRequest, client sqlc.Client) error {
+	ctx := c.Request().Context()
+
+	if req.Code == "" {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidRequest, "code is required")
+	}
+
+	// Codes are stored hashed
+	authCode, err := h.store.GetAuthorizationCodeByCode(ctx, utils.HashToken(req.Code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Invalid authorization code")
+		}
+		return newServerError("Failed to fetch authorization code", err).respond(c)
+	}
+
+	if authCode.ClientID != client.ID {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Invalid authorization code")
+	}
+
+	if authCode.IsUsed.Valid && authCode.IsUsed.Bool {
+		return h.rejectReplayedCode(c, authCode)
+	}
+
+	if time.Now().After(authCode.ExpiresAt) {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Authorization code has expired")
+	}
+
+	// A redirect_uri sent with the authorization request has to be repeated exactly
+	if authCode.RedirectUriSent && req.RedirectURI == "" {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidRequest, "redirect_uri is required")
+	}
+	if req.RedirectURI != "" && req.RedirectURI != authCode.RedirectUri {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "redirect_uri does not match the authorization request")
+	}
+
+	// Verify the PKCE code verifier against the stored challenge
+	if authCode.CodeChallenge.Valid {
+		if !verifyCodeChallenge(authCode.CodeChallenge.String, authCode.CodeChallengeMethod.String, req.CodeVerifier) {
+			return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Invalid code_verifier")
+		}
+	} else if req.CodeVerifier != "" {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "code_verifier was sent but no code_challenge was used")
+	}
+
+	user, err := h.store.GetUserByID(ctx, authCode.UserID)
+	if err != nil {
+		return newServerError("Failed to fetch user", err).respond(c)
+	}
+	if user.Active.Valid && !user.Active.Bool {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "User account is not active")
+	}
+
+	// Mark the code as used, only one concurrent exchange can win. The access token it is
+	// exchanged for is recorded with it, a replayed code revokes it. Both happen in one
+	// transaction so that a failure to issue the tokens leaves the code usable.
+	accessTokenID := uuid.NewString()
+	var res *TokenResponse
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		updated, err := q.MarkAuthorizationCodeUsed(ctx, sqlc.MarkAuthorizationCodeUsedParams{
+			ID:             authCode.ID,
+			AccessTokenJti: sql.NullString{String: accessTokenID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return errAuthorizationCodeUsed
+		}
+
+		res, err = h.issueUserTokens(ctx, q, userTokenGrant{
+			User:                user,
+			Client:              client,
+			Scope:               authCode.Scope,
+			AuthorizationCodeID: uuid.NullUUID{UUID: authCode.ID, Valid: true},
+			Nonce:               authCode.Nonce.String,
+			AuthTime:            authCode.AuthTime,
+			SessionID:           authCode.SessionID,
+			AccessTokenID:       accessTokenID,
+		})
+		return err
+	})
+	if errors.Is(err, errAuthorizationCodeUsed) {
+		// Look up the access token the concurrent exchange recorded
+		if used, err := h.store.GetAuthorizationCodeByCode(ctx, authCode.Code); err == nil {
+			authCode = used
+		}
+		return h.rejectReplayedCode(c, authCode)
+	}
+	if err != nil {
+		return newServerError("Failed to issue tokens", err).respond(c)
+	}
+
+	return respondWithToken(c, res)
+}
+
+// rejectReplayedCode revokes the tokens previously issued from a code that is presented again,
+// since the code has most likely been stolen (RFC 6749 section 4.1.2)
+func (h *OAuthHandler) rejectReplayedCode(c echo.Context, authCode sqlc.AuthorizationCode) error {
+	log.Printf("Authorization code %s was replayed, revoking issued tokens", authCode.ID)
+
+	ctx := c.Request().Context()
+
+	err := h.store.DeactivateRefreshTokensByAuthorizationCode(ctx, uuid.NullUUID{UUID: authCode.ID, Valid: true})
+	if err != nil {
+		return newServerError("Failed to revoke tokens of replayed authorization code", err).respond(c)
+	}
+
+	// The access token was issued when the code was used, it expires within its lifetime from now
+	if authCode.AccessTokenJti.Valid {
+		err = h.store.RevokeAccessToken(ctx, sqlc.RevokeAccessTokenParams{
+			Jti:       authCode.AccessTokenJti.String,
+			ExpiresAt: time.Now().Add(time.Duration(h.config.JWT.ExpiryHours) * time.Hour),
+		})
+		if err != nil {
+			return newServerError("Failed to revoke tokens of replayed authorization code", err).respond(c)
+		}
+	}
+
+	return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Authorization code has already been used")
+}
@@ -0,0 +1,275 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// authorizationRequest is an authorization request that passed validation
+type authorizationRequest struct {
+	*AuthorizeRequest
+	Client              sqlc.Client
+	RedirectURI         string
+	Scope               string       // Normalized requested scope
+	Scopes              []sqlc.Scope // Registry entries of the requested scopes
+	CodeChallengeMethod string
+}
+
+// authorizeError is a rejected authorization request. Until the redirect URI is verified the
+// error can only be shown to the user, afterwards it is sent back to the client.
+type authorizeError struct {
+	redirectURI string
+	state       string
+	code        utils.OAuthErrorCode
+	description string
+}
+
+// respond shows the error or redirects back to the client with it
+func (e *authorizeError) respond(c echo.Context) error {
+	if e.redirectURI == "" {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid authorization request",
+			utils.ErrorCodeInvalidRequest,
+			e.description,
+			nil,
+		)
+	}
+	return redirectWithError(c, e.redirectURI, e.state, e.code, e.description)
+}
+
+// Authorize handles the OAuth 2.0 authorization endpoint and issues authorization codes
+// to logged-in users
+func (h *OAuthHandler) Authorize(c echo.Context) error {
+	// Parse the query parameters
+	req := new(AuthorizeRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse authorization request",
+			err,
+		)
+	}
+
+	// Validate the request data
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	areq, authErr, err := h.validateAuthorizeRequest(ctx, req)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to validate authorization request", err)
+	}
+	if authErr != nil {
+		return authErr.respond(c)
+	}
+
+	// The user must be logged in, otherwise send them to the login page and back here afterwards
+	session, err := h.getSession(c)
+	if err != nil {
+		return redirectWithError(c, areq.RedirectURI, req.State, utils.OAuthErrorServerError, "")
+	}
+	if session == nil {
+		returnTo := c.Scheme() + "://" + c.Request().Host + c.Request().URL.RequestURI()
+		return redirectWithParams(c, h.config.OAuth.LoginURL, map[string]string{
+			"redirect": returnTo,
+		})
+	}
+
+	// Make sure the account is still allowed to sign in
+	user, err := h.store.GetUserByID(ctx, session.UserID)
+	if err != nil || (user.Active.Valid && !user.Active.Bool) {
+		return redirectWithError(c, areq.RedirectURI, req.State, utils.OAuthErrorAccessDenied, "User account is not active")
+	}
+
+	// Third-party clients need the user's consent for scopes not granted before. The consent
+	// page receives the authorization request as is and submits it back with the decision.
+	if !areq.Client.IsFirstParty {
+		needed, err := h.needsConsent(ctx, user.ID, areq)
+		if err != nil {
+			return redirectWithError(c, areq.RedirectURI, req.State, utils.OAuthErrorServerError, "")
+		}
+		if needed {
+			consentURL, err := url.Parse(h.config.OAuth.ConsentURL)
+			if err != nil {
+				return utils.RespondWithInternalError(c, "Failed to build consent URL", err)
+			}
+			consentURL.RawQuery = c.QueryString()
+			return c.Redirect(http.StatusFound, consentURL.String())
+		}
+	}
+
+	location, err := h.issueAuthorizationCode(ctx, areq, user, session)
+	if err != nil {
+		return redirectWithError(c, areq.RedirectURI, req.State, utils.OAuthErrorServerError, "")
+	}
+	return c.Redirect(http.StatusFound, location)
+}
+
+// validateAuthorizeRequest checks an authorization request against the client registration
+// and the scope registry. Database failures are returned as err.
+func (h *OAuthHandler) validateAuthorizeRequest(ctx context.Context, req *AuthorizeRequest) (*authorizationRequest, *authorizeError, error) {
+	// Look up the client requesting authorization
+	client, err := h.store.GetClientByClientId(ctx, req.ClientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &authorizeError{description: "Unknown or inactive client_id"}, nil
+		}
+		return nil, nil, err
+	}
+
+	// The redirect URI must be verified before any error can be sent back to it
+	redirectURI, ok := resolveRedirectURI(client.RedirectUris, req.RedirectURI)
+	if !ok {
+		return nil, &authorizeError{description: "redirect_uri is missing or not registered for this client"}, nil
+	}
+
+	fail := func(code utils.OAuthErrorCode, description string) (*authorizationRequest, *authorizeError, error) {
+		return nil, &authorizeError{redirectURI: redirectURI, state: req.State, code: code, description: description}, nil
+	}
+
+	if req.ResponseType != "code" {
+		return fail(utils.OAuthErrorUnsupportedResponseType, "Only the code response type is supported")
+	}
+
+	if !slices.Contains(client.GrantTypes, grantTypeAuthorizationCode) {
+		return fail(utils.OAuthErrorUnauthorizedClient, "The client is not allowed to use the authorization code grant")
+	}
+
+	scope, scopes, description, err := h.validateRequestedScope(ctx, client, req.Scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	if description != "" {
+		return fail(utils.OAuthErrorInvalidScope, description)
+	}
+
+	// Validate the PKCE parameters, public clients cannot keep a secret and must use S256
+	codeChallengeMethod := req.CodeChallengeMethod
+	if req.CodeChallenge == "" {
+		if !isConfidential(client) {
+			return fail(utils.OAuthErrorInvalidRequest, "code_challenge is required for public clients")
+		}
+		if codeChallengeMethod != "" {
+			return fail(utils.OAuthErrorInvalidRequest, "code_challenge_method was sent without code_challenge")
+		}
+	} else {
+		if codeChallengeMethod == "" {
+			codeChallengeMethod = codeChallengeMethodPlain
+		}
+		if codeChallengeMethod != codeChallengeMethodS256 && codeChallengeMethod != codeChallengeMethodPlain {
+			return fail(utils.OAuthErrorInvalidRequest, "Unsupported code_challenge_method")
+		}
+		if !isConfidential(client) && codeChallengeMethod != codeChallengeMethodS256 {
+			return fail(utils.OAuthErrorInvalidRequest, "Public clients must use the S256 code_challenge_method")
+		}
+		if !isValidPKCEValue(req.CodeChallenge) {
+			return fail(utils.OAuthErrorInvalidRequest, "Malformed code_challenge")
+		}
+	}
+
+	return &authorizationRequest{
+		AuthorizeRequest:    req,
+		Client:              client,
+		RedirectURI:         redirectURI,
+		Scope:               scope,
+		Scopes:              scopes,
+		CodeChallengeMethod: codeChallengeMethod,
+	}, nil, nil
+}
+
+// issueAuthorizationCode creates a single-use authorization code for the user and returns
+// the redirect URI carrying it back to the client
+func (h *OAuthHandler) issueAuthorizationCode(ctx context.Context, areq *authorizationRequest, user sqlc.User, session *sqlc.Session) (string, error) {
+	// Only the hash of the code is stored
+	code, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = h.store.CreateAuthorizationCode(ctx, sqlc.CreateAuthorizationCodeParams{
+		UserID:              user.ID,
+		ClientID:            areq.Client.ID,
+		Code:                utils.HashToken(code),
+		RedirectUri:         areq.RedirectURI,
+		Scope:               areq.Scope,
+		CodeChallenge:       sql.NullString{String: areq.CodeChallenge, Valid: areq.CodeChallenge != ""},
+		CodeChallengeMethod: sql.NullString{String: areq.CodeChallengeMethod, Valid: areq.CodeChallenge != ""},
+		Nonce:               sql.NullString{String: areq.Nonce, Valid: areq.Nonce != ""},
+		AuthTime:            session.CreatedAt,
+		ExpiresAt:           time.Now().Add(h.config.OAuth.AuthorizationCodeExpiry),
+		SessionID:           uuid.NullUUID{UUID: session.ID, Valid: true},
+		RedirectUriSent:     areq.AuthorizeRequest.RedirectURI != "",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return buildRedirectURL(areq.RedirectURI, map[string]string{
+		"code":  code,
+		"state": areq.State,
+	})
+}
+
+// resolveRedirectURI returns the redirect URI to use for a request. A client with a
+// single registered URI may omit it, otherwise it must exactly match a registered one.
+func resolveRedirectURI(registered []string, requested string) (string, bool) {
+	if requested == "" {
+		if len(registered) == 1 {
+			return registered[0], true
+		}
+		return "", false
+	}
+	return requested, slices.Contains(registered, requested)
+}
+
+// buildRedirectURL adds the non-empty params to the query of the given URL
+func buildRedirectURL(target string, params map[string]string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	for key, value := range params {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// redirectWithParams redirects to the given URL with the non-empty params added to its query
+func redirectWithParams(c echo.Context, target string, params map[string]string) error {
+	location, err := buildRedirectURL(target, params)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to build redirect URL", err)
+	}
+	return c.Redirect(http.StatusFound, location)
+}
+
+// redirectWithError reports an authorization error back to the client's redirect URI
+func redirectWithError(c echo.Context, redirectURI, state string, code utils.OAuthErrorCode, description string) error {
+	return redirectWithParams(c, redirectURI, map[string]string{
+		"error":             string(code),
+		"error_description": description,
+		"state":             state,
+	})
+}
@@ -0,0 +1,117 @@
+package oauth
+
+import (
+	"database/sql"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ListAuthorizedApps lists the clients the authenticated user has granted consent to
+func (h *OAuthHandler) ListAuthorizedApps(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	consents, err := h.store.ListUserConsents(c.Request().Context(), userID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch authorized apps", err)
+	}
+
+	res := make([]AuthorizedAppResponse, 0, len(consents))
+	for _, consent := range consents {
+		res = append(res, AuthorizedAppResponse{
+			ClientID:    consent.ClientID,
+			Name:        consent.Name,
+			Description: consent.Description.String,
+			WebsiteURL:  consent.WebsiteUrl.String,
+			Scopes:      consent.Scopes,
+			GrantedAt:   consent.CreatedAt.Time,
+			UpdatedAt:   consent.UpdatedAt.Time,
+		})
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Authorized apps retrieved successfully",
+		res,
+	)
+}
+
+// RevokeAuthorizedApp withdraws the consent given to a client and revokes the refresh tokens
+// it holds for the user, so it has to ask for consent again
+func (h *OAuthHandler) RevokeAuthorizedApp(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return respondWithUnauthenticated(c)
+	}
+
+	ctx := c.Request().Context()
+
+	client, err := h.store.GetClientByClientId(ctx, c.Param("client_id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respondWithAppNotFound(c)
+		}
+		return utils.RespondWithInternalError(c, "Failed to fetch client", err)
+	}
+
+	deleted, err := h.store.DeleteUserConsent(ctx, sqlc.DeleteUserConsentParams{
+		UserID:   userID,
+		ClientID: client.ID,
+	})
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to revoke consent", err)
+	}
+	if deleted == 0 {
+		return respondWithAppNotFound(c)
+	}
+
+	err = h.store.DeactivateUserClientRefreshTokens(ctx, sqlc.DeactivateUserClientRefreshTokensParams{
+		UserID:   userID,
+		ClientID: client.ID,
+	})
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to revoke refresh tokens", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"App access revoked successfully",
+		nil,
+	)
+}
+
+// currentUserID returns the ID of the user set by the auth middleware
+func currentUserID(c echo.Context) (uuid.UUID, bool) {
+	userIDStr, _ := c.Get("user_id").(string)
+	userID, err := uuid.Parse(userIDStr)
+	return userID, err == nil
+}
+
+func respondWithUnauthenticated(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeUnauthorized,
+		"Unauthorized",
+		utils.ErrorCodeUnauthorized,
+		"User not authenticated",
+		nil,
+	)
+}
+
+func respondWithAppNotFound(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeNotFound,
+		"App not found",
+		utils.ErrorCodeResourceNotFound,
+		"The app has not been authorized",
+		nil,
+	)
+}
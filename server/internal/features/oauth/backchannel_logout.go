@@ -0,0 +1,93 @@
+package oauth
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// LogoutNotifier ends what logged out login sessions left behind at the clients. The refresh
+// tokens issued to the sessions are revoked and every client the sessions authorized that
+// registered a backchannel_logout_uri is sent a logout token (OpenID Connect Back-Channel Logout 1.0).
+type LogoutNotifier struct {
+	store  *db.Store
+	client *http.Client
+}
+
+// NewLogoutNotifier creates a notifier delivering logout tokens with a short timeout
+func NewLogoutNotifier(store *db.Store) *LogoutNotifier {
+	return &LogoutNotifier{
+		store:  store,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// SessionsEnded is called when login sessions of a user are logged out. Logout tokens are
+// delivered in the background so that a slow client cannot hold up the logout.
+func (n *LogoutNotifier) SessionsEnded(ctx context.Context, userID uuid.UUID, sessionIDs []uuid.UUID) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	if err := n.store.DeactivateSessionRefreshTokens(ctx, sessionIDs); err != nil {
+		return err
+	}
+
+	clients, err := n.store.ListSessionBackchannelLogoutClients(ctx, sessionIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, client := range clients {
+		claims := utils.LogoutTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:  userID.String(),
+				Audience: jwt.ClaimStrings{client.ClientID},
+			},
+		}
+		if client.SessionID.Valid {
+			claims.SessionID = client.SessionID.UUID.String()
+		}
+
+		logoutToken, err := utils.CreateLogoutToken(claims)
+		if err != nil {
+			return err
+		}
+
+		go n.deliver(client.ClientID, client.BackchannelLogoutUri.String, logoutToken)
+	}
+
+	return nil
+}
+
+// deliver posts a logout token to a client's backchannel_logout_uri. Failures are only
+// logged, the user is logged out of CentralAuth either way.
+func (n *LogoutNotifier) deliver(clientID, logoutURI, logoutToken string) {
+	form := url.Values{"logout_token": {logoutToken}}
+
+	req, err := http.NewRequest(http.MethodPost, logoutURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		log.Printf("Back-channel logout of client %s failed: %v", clientID, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		log.Printf("Back-channel logout of client %s failed: %v", clientID, err)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		log.Printf("Back-channel logout of client %s failed: unexpected status %d", clientID, res.StatusCode)
+	}
+}
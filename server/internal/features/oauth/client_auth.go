@@ -0,0 +1,283 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// Token endpoint authentication methods (RFC 7591 section 2)
+const (
+	authMethodClientSecretBasic = "client_secret_basic"
+	authMethodClientSecretPost  = "client_secret_post"
+	authMethodClientSecretJWT   = "client_secret_jwt"
+	authMethodPrivateKeyJWT     = "private_key_jwt"
+	authMethodNone              = "none"
+)
+
+var supportedAuthMethods = []string{
+	authMethodClientSecretBasic,
+	authMethodClientSecretPost,
+	authMethodClientSecretJWT,
+	authMethodPrivateKeyJWT,
+	authMethodNone,
+}
+
+// clientAssertionTypeJWTBearer is the only supported client assertion type (RFC 7523 section 2.2)
+const clientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// maxClientAssertionLifetime bounds how far in the future a client assertion may expire, which
+// also bounds how long its jti has to be remembered
+const maxClientAssertionLifetime = time.Hour
+
+// Signing algorithms accepted for client assertions
+var (
+	clientSecretJWTAlgorithms = []string{"HS256", "HS384", "HS512"}
+	privateKeyJWTAlgorithms   = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+)
+
+// authenticateClient identifies the client calling a back-channel OAuth endpoint. Confidential
+// clients authenticate with the method they are registered for, either their client secret or
+// a JWT assertion, public clients only identify themselves with their client_id.
+func (h *OAuthHandler) authenticateClient(c echo.Context, creds ClientCredentials) (sqlc.Client, *oauthError) {
+	if creds.ClientAssertionType != "" || creds.ClientAssertion != "" {
+		return h.authenticateClientAssertion(c, creds)
+	}
+
+	clientID, clientSecret := creds.ClientID, creds.ClientSecret
+
+	// client_secret_basic, credentials are form encoded before being placed in the header
+	if basicID, basicSecret, ok := c.Request().BasicAuth(); ok {
+		if clientSecret != "" {
+			return sqlc.Client{}, newOAuthError(utils.StatusCodeBadRequest, utils.OAuthErrorInvalidRequest, "Only one client authentication method may be used")
+		}
+
+		id, idErr := url.QueryUnescape(basicID)
+		secret, secretErr := url.QueryUnescape(basicSecret)
+		if idErr != nil || secretErr != nil || (clientID != "" && clientID != id) {
+			return sqlc.Client{}, newOAuthError(utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidClient, "Client authentication failed")
+		}
+		clientID, clientSecret = id, secret
+	}
+
+	if clientID == "" {
+		return sqlc.Client{}, newOAuthError(utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidClient, "Client authentication failed")
+	}
+
+	client, err := h.store.GetClientByClientId(c.Request().Context(), clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sqlc.Client{}, newOAuthError(utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidClient, "Client authentication failed")
+		}
+		return sqlc.Client{}, newServerError("Failed to fetch client", err)
+	}
+
+	// Clients registered for JWT authentication must never fall back to sending their secret
+	if usesClientAssertion(client) {
+		return sqlc.Client{}, newOAuthError(utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidClient, "The client must authenticate with a client assertion")
+	}
+
+	if isConfidential(client) {
+		if clientSecret == "" || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(client.ClientSecret)) != 1 {
+			return sqlc.Client{}, newOAuthError(utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidClient, "Client authentication failed")
+		}
+	}
+
+	return client, nil
+}
+
+// authenticateClientAssertion authenticates a client with a JWT assertion signed with its
+// client secret (client_secret_jwt) or its private key (private_key_jwt) as described in
+// RFC 7523 section 3 and OpenID Connect Core section 9
+func (h *OAuthHandler) authenticateClientAssertion(c echo.Context, creds ClientCredentials) (sqlc.Client, *oauthError) {
+	fail := func(description string) (sqlc.Client, *oauthError) {
+		return sqlc.Client{}, newOAuthError(utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidClient, description)
+	}
+
+	if creds.ClientAssertionType != clientAssertionTypeJWTBearer || creds.ClientAssertion == "" {
+		return fail("Unsupported client_assertion_type")
+	}
+	if _, _, ok := c.Request().BasicAuth(); ok || creds.ClientSecret != "" {
+		return sqlc.Client{}, newOAuthError(utils.StatusCodeBadRequest, utils.OAuthErrorInvalidRequest, "Only one client authentication method may be used")
+	}
+
+	// The client is identified by the subject of the assertion, its signature is checked below
+	unverified := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(creds.ClientAssertion, &unverified); err != nil {
+		return fail("Malformed client assertion")
+	}
+	if unverified.Subject == "" || (creds.ClientID != "" && creds.ClientID != unverified.Subject) {
+		return fail("Client authentication failed")
+	}
+
+	ctx := c.Request().Context()
+
+	client, err := h.store.GetClientByClientId(ctx, unverified.Subject)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fail("Client authentication failed")
+		}
+		return sqlc.Client{}, newServerError("Failed to fetch client", err)
+	}
+
+	var algorithms []string
+	var keyFunc jwt.Keyfunc
+	switch client.TokenEndpointAuthMethod {
+	case authMethodClientSecretJWT:
+		algorithms = clientSecretJWTAlgorithms
+		keyFunc = func(*jwt.Token) (any, error) {
+			return []byte(client.ClientSecret), nil
+		}
+	case authMethodPrivateKeyJWT:
+		algorithms = privateKeyJWTAlgorithms
+		keyFunc = func(token *jwt.Token) (any, error) {
+			return h.clientVerificationKeys(ctx, client, token)
+		}
+	default:
+		return fail("The client is not registered for client assertion authentication")
+	}
+
+	claims := jwt.RegisteredClaims{}
+	_, err = jwt.NewParser(
+		jwt.WithValidMethods(algorithms),
+		jwt.WithIssuer(client.ClientID),
+		jwt.WithSubject(client.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	).ParseWithClaims(creds.ClientAssertion, &claims, keyFunc)
+	if err != nil {
+		if errors.Is(err, errClientKeysUnavailable) {
+			return sqlc.Client{}, newServerError("Failed to fetch client keys", err)
+		}
+		return fail("Invalid client assertion")
+	}
+
+	if problem := checkClientAssertionClaims(claims, h.config.JWT.Issuer, c.Request().URL.Path, time.Now()); problem != "" {
+		return fail(problem)
+	}
+
+	// Each assertion can only be used once
+	recorded, err := h.store.RecordClientAssertionJTI(ctx, sqlc.RecordClientAssertionJTIParams{
+		ClientID:  client.ID,
+		Jti:       claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return sqlc.Client{}, newServerError("Failed to record client assertion", err)
+	}
+	if recorded == 0 {
+		return fail("The client assertion has already been used")
+	}
+
+	if _, err := h.store.DeleteExpiredClientAssertionJTIs(ctx); err != nil {
+		log.Printf("Failed to delete expired client assertion identifiers: %v", err)
+	}
+
+	return client, nil
+}
+
+// checkClientAssertionClaims checks the claims of a client assertion with a verified signature
+// presented at path. Problems are returned as a description.
+func checkClientAssertionClaims(claims jwt.RegisteredClaims, issuer, path string, now time.Time) string {
+	// The assertion must be meant for this server, either as a whole or for the endpoint called
+	audiences := []string{issuer, issuer + path}
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool { return slices.Contains(audiences, aud) }) {
+		return "The client assertion audience does not match"
+	}
+
+	if claims.ID == "" {
+		return "The client assertion has no jti"
+	}
+	if claims.ExpiresAt == nil || claims.ExpiresAt.After(now.Add(maxClientAssertionLifetime)) {
+		return "The client assertion expires too far in the future"
+	}
+	return ""
+}
+
+// clientVerificationKeys returns the registered public keys an assertion of the client may be
+// signed with, matching the kid and algorithm of its header. Keys fetched from a jwks_uri are
+// refreshed once when no key matches, so clients can rotate their keys.
+func (h *OAuthHandler) clientVerificationKeys(ctx context.Context, client sqlc.Client, token *jwt.Token) (jwt.VerificationKeySet, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		jwks, err := h.clientJWKS(ctx, client, attempt > 0)
+		if err != nil {
+			return jwt.VerificationKeySet{}, err
+		}
+
+		keys := jwt.VerificationKeySet{}
+		for _, jwk := range jwks.Keys {
+			if !jwkMatches(jwk, kid, token.Method.Alg()) {
+				continue
+			}
+			key, err := jwk.PublicKey()
+			if err != nil {
+				continue
+			}
+			keys.Keys = append(keys.Keys, key)
+		}
+		if len(keys.Keys) > 0 {
+			return keys, nil
+		}
+		if !client.JwksUri.Valid {
+			break
+		}
+	}
+
+	return jwt.VerificationKeySet{}, errors.New("no registered key matches the client assertion")
+}
+
+// clientJWKS returns the key set registered inline for a client or published at its jwks_uri
+func (h *OAuthHandler) clientJWKS(ctx context.Context, client sqlc.Client, refresh bool) (utils.JWKS, error) {
+	if client.Jwks.Valid {
+		return utils.ParseJWKS([]byte(client.Jwks.String))
+	}
+	if client.JwksUri.Valid {
+		return h.jwksCache.get(ctx, client.JwksUri.String, refresh)
+	}
+	return utils.JWKS{}, errors.New("the client has no registered keys")
+}
+
+// jwkMatches reports whether a JWK can verify a signature with the given kid and algorithm
+func jwkMatches(jwk utils.JWK, kid, alg string) bool {
+	if kid != "" && jwk.Kid != kid {
+		return false
+	}
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return false
+	}
+	if jwk.Alg != "" && jwk.Alg != alg {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"):
+		return jwk.Kty == "RSA"
+	case strings.HasPrefix(alg, "ES"):
+		return jwk.Kty == "EC"
+	default:
+		return false
+	}
+}
+
+// usesClientAssertion reports whether a client is registered to authenticate with a JWT assertion
+func usesClientAssertion(client sqlc.Client) bool {
+	return client.TokenEndpointAuthMethod == authMethodClientSecretJWT || client.TokenEndpointAuthMethod == authMethodPrivateKeyJWT
+}
+
+// isConfidential reports whether a client is able to keep a secret. The column defaults
+// to true, so a missing value is treated as confidential.
+func isConfidential(client sqlc.Client) bool {
+	return !client.IsConfidential.Valid || client.IsConfidential.Bool
+}
@@ -0,0 +1,52 @@
+package oauth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestCheckClientAssertionClaims(t *testing.T) {
+	const (
+		issuer = "https://auth.example.com"
+		path   = "/oauth/token"
+	)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	claims := func(audience []string, jti string, expiresAt time.Time) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Audience:  audience,
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		}
+	}
+	soon := now.Add(5 * time.Minute)
+
+	tests := []struct {
+		name   string
+		claims jwt.RegisteredClaims
+		valid  bool
+	}{
+		{"issuer audience", claims([]string{issuer}, "jti-1", soon), true},
+		{"endpoint audience", claims([]string{issuer + path}, "jti-1", soon), true},
+		{"one of several audiences", claims([]string{"https://other.example.com", issuer}, "jti-1", soon), true},
+		{"other endpoint audience", claims([]string{issuer + "/oauth/introspect"}, "jti-1", soon), false},
+		{"audience with trailing slash", claims([]string{issuer + "/"}, "jti-1", soon), false},
+		{"foreign audience", claims([]string{"https://other.example.com"}, "jti-1", soon), false},
+		{"no audience", claims(nil, "jti-1", soon), false},
+		{"no jti", claims([]string{issuer}, "", soon), false},
+		{"expires at the longest lifetime", claims([]string{issuer}, "jti-1", now.Add(maxClientAssertionLifetime)), true},
+		{"expires after the longest lifetime", claims([]string{issuer}, "jti-1", now.Add(maxClientAssertionLifetime+time.Second)), false},
+		{"no expiry", jwt.RegisteredClaims{Audience: []string{issuer}, ID: "jti-1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := checkClientAssertionClaims(tt.claims, issuer, path, now)
+			if (problem == "") != tt.valid {
+				t.Errorf("checkClientAssertionClaims() = %q, want valid = %v", problem, tt.valid)
+			}
+		})
+	}
+}
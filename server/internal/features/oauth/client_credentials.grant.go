@@ -0,0 +1,50 @@
+package oauth
+
+import (
+	"strings"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// clientCredentialsGrant issues an access token to a client acting on its own behalf
+// (RFC 6749 section 4.4). The token has the client as subject and carries no user.
+func (h *OAuthHandler) clientCredentialsGrant(c echo.Context, req *TokenRequest, client sqlc.Client) error {
+	// Public clients cannot prove their identity
+	if !isConfidential(client) {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorUnauthorizedClient, "The client_credentials grant requires a confidential client")
+	}
+
+	// Without an explicit scope the client gets every scope it is allowed
+	allowedScope := strings.Join(client.AllowedScopes, " ")
+	scope := allowedScope
+	if req.Scope != "" {
+		if !isScopeSubset(req.Scope, allowedScope) {
+			return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidScope, "The requested scope is not allowed for this client")
+		}
+		scope = normalizeScope(req.Scope)
+	}
+
+	claims := utils.AccessTokenClaims{
+		ClientID: client.ClientID,
+		Scope:    scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: client.ClientID,
+		},
+	}
+
+	accessToken, expiresIn, err := utils.CreateAccessToken(claims)
+	if err != nil {
+		return newServerError("Failed to create access token", err).respond(c)
+	}
+
+	// No refresh token is issued, the client can simply request a new access token
+	return respondWithToken(c, &TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   expiresIn,
+		Scope:       scope,
+	})
+}
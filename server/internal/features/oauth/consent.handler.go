@@ -0,0 +1,246 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// GetConsent describes the client and scopes of an authorization request so the consent
+// page can ask the logged-in user for approval
+func (h *OAuthHandler) GetConsent(c echo.Context) error {
+	req := new(AuthorizeRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse authorization request",
+			err,
+		)
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	session, err := h.requireSession(c)
+	if session == nil {
+		return err
+	}
+
+	areq, authErr, err := h.validateAuthorizeRequest(ctx, req)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to validate authorization request", err)
+	}
+	if authErr != nil {
+		return respondWithConsentError(c, authErr)
+	}
+
+	granted, err := h.grantedScopes(ctx, session.UserID, areq.Client.ID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch consent", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Consent request retrieved successfully",
+		buildConsentResponse(toConsentClientResponse(areq.Client), areq.Scopes, granted),
+	)
+}
+
+// SubmitConsent records the user's decision on an authorization request. Approved scopes are
+// remembered for the client and the authorization code is issued, either way the consent page
+// is told where to send the user next.
+func (h *OAuthHandler) SubmitConsent(c echo.Context) error {
+	req := new(ConsentRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	session, err := h.requireSession(c)
+	if session == nil {
+		return err
+	}
+
+	areq, authErr, err := h.validateAuthorizeRequest(ctx, &req.AuthorizeRequest)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to validate authorization request", err)
+	}
+	if authErr != nil {
+		return respondWithConsentError(c, authErr)
+	}
+
+	if !req.Approve {
+		location, err := buildRedirectURL(areq.RedirectURI, map[string]string{
+			"error":             string(utils.OAuthErrorAccessDenied),
+			"error_description": "The user denied the request",
+			"state":             areq.State,
+		})
+		if err != nil {
+			return utils.RespondWithInternalError(c, "Failed to build redirect URL", err)
+		}
+		return respondWithConsentDecision(c, location)
+	}
+
+	user, err := h.store.GetUserByID(ctx, session.UserID)
+	if err != nil || (user.Active.Valid && !user.Active.Bool) {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeForbidden,
+			"Forbidden",
+			utils.ErrorCodeForbidden,
+			"User account is not active",
+			nil,
+		)
+	}
+
+	if err := h.rememberConsent(ctx, user.ID, areq.Client.ID, areq.Scope); err != nil {
+		return utils.RespondWithInternalError(c, "Failed to save consent", err)
+	}
+
+	location, err := h.issueAuthorizationCode(ctx, areq, user, session)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to issue authorization code", err)
+	}
+	return respondWithConsentDecision(c, location)
+}
+
+// needsConsent reports whether the request asks for a scope requiring consent that the user
+// has not granted to the client yet
+func (h *OAuthHandler) needsConsent(ctx context.Context, userID uuid.UUID, areq *authorizationRequest) (bool, error) {
+	granted, err := h.grantedScopes(ctx, userID, areq.Client.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, scope := range areq.Scopes {
+		if scope.RequiresConsent && !slices.Contains(granted, scope.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rememberConsent records the approved scopes together with the ones granted to the client before
+func (h *OAuthHandler) rememberConsent(ctx context.Context, userID, clientID uuid.UUID, scope string) error {
+	granted, err := h.grantedScopes(ctx, userID, clientID)
+	if err != nil {
+		return err
+	}
+	for _, s := range uniqueScopes(scope) {
+		if !slices.Contains(granted, s) {
+			granted = append(granted, s)
+		}
+	}
+
+	_, err = h.store.UpsertUserConsent(ctx, sqlc.UpsertUserConsentParams{
+		UserID:   userID,
+		ClientID: clientID,
+		Scopes:   granted,
+	})
+	return err
+}
+
+// grantedScopes returns the scopes the user has granted to the client so far
+func (h *OAuthHandler) grantedScopes(ctx context.Context, userID, clientID uuid.UUID) ([]string, error) {
+	consent, err := h.store.GetUserConsent(ctx, sqlc.GetUserConsentParams{
+		UserID:   userID,
+		ClientID: clientID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	return consent.Scopes, nil
+}
+
+// requireSession returns the session of the logged-in user. When there is none the error
+// response has already been sent and its result is returned instead.
+func (h *OAuthHandler) requireSession(c echo.Context) (*sqlc.Session, error) {
+	session, err := h.getSession(c)
+	if err != nil {
+		return nil, utils.RespondWithInternalError(c, "Failed to fetch session", err)
+	}
+	if session == nil {
+		return nil, utils.RespondWithError(
+			c,
+			utils.StatusCodeUnauthorized,
+			"Unauthorized",
+			utils.ErrorCodeUnauthorized,
+			"No active session found",
+			nil,
+		)
+	}
+	return session, nil
+}
+
+// buildConsentResponse describes a client and the scopes it requests to the user
+func buildConsentResponse(client ConsentClientResponse, scopes []sqlc.Scope, granted []string) ConsentResponse {
+	res := ConsentResponse{
+		Client: client,
+		Scopes: make([]ConsentScopeResponse, 0, len(scopes)),
+	}
+	for _, scope := range scopes {
+		res.Scopes = append(res.Scopes, ConsentScopeResponse{
+			Name:            scope.Name,
+			Description:     scope.Description,
+			RequiresConsent: scope.RequiresConsent,
+			Granted:         slices.Contains(granted, scope.Name),
+		})
+	}
+	return res
+}
+
+func toConsentClientResponse(client sqlc.Client) ConsentClientResponse {
+	return ConsentClientResponse{
+		ClientID:    client.ClientID,
+		Name:        client.Name,
+		Description: client.Description.String,
+		WebsiteURL:  client.WebsiteUrl.String,
+	}
+}
+
+// respondWithConsentError reports an invalid authorization request to the consent page
+func respondWithConsentError(c echo.Context, authErr *authorizeError) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeBadRequest,
+		"Invalid authorization request",
+		utils.ErrorCodeInvalidRequest,
+		authErr.description,
+		map[string]string{"error": string(authErr.code)},
+	)
+}
+
+// respondWithConsentDecision tells the consent page where to send the user
+func respondWithConsentDecision(c echo.Context, location string) error {
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Consent recorded successfully",
+		ConsentDecisionResponse{RedirectTo: location},
+	)
+}
@@ -0,0 +1,92 @@
+package oauth
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// DeviceAuthorization handles the device authorization endpoint (RFC 8628 section 3.1). Devices
+// without a browser get a device code to poll the token endpoint with and a user code the user
+// enters on the verification page of another device.
+func (h *OAuthHandler) DeviceAuthorization(c echo.Context) error {
+	req := new(DeviceAuthorizationRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorInvalidRequest,
+			"Could not parse device authorization request",
+		)
+	}
+
+	client, oauthErr := h.authenticateClient(c, req.ClientCredentials)
+	if oauthErr != nil {
+		return oauthErr.respond(c)
+	}
+
+	if !slices.Contains(client.GrantTypes, grantTypeDeviceCode) {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorUnauthorizedClient, "The client is not allowed to use the device authorization grant")
+	}
+
+	ctx := c.Request().Context()
+
+	scope, _, description, err := h.validateRequestedScope(ctx, client, req.Scope)
+	if err != nil {
+		return newServerError("Failed to validate scope", err).respond(c)
+	}
+	if description != "" {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidScope, description)
+	}
+
+	// Expired requests are no longer needed and free up their user codes
+	if _, err := h.store.DeleteExpiredDeviceCodes(ctx); err != nil {
+		log.Printf("Failed to delete expired device codes: %v", err)
+	}
+
+	// Only the hash of the device code is stored, the user code is short lived and typed by the user
+	deviceCode, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return newServerError("Failed to generate device code", err).respond(c)
+	}
+	userCode, err := generateUserCode()
+	if err != nil {
+		return newServerError("Failed to generate user code", err).respond(c)
+	}
+
+	expiry := h.config.OAuth.DeviceCodeExpiry
+	interval := int(h.config.OAuth.DevicePollInterval.Seconds())
+
+	_, err = h.store.CreateDeviceCode(ctx, sqlc.CreateDeviceCodeParams{
+		ClientID:     client.ID,
+		DeviceCode:   utils.HashToken(deviceCode),
+		UserCode:     userCode,
+		Scope:        scope,
+		PollInterval: int32(interval),
+		ExpiresAt:    time.Now().Add(expiry),
+	})
+	if err != nil {
+		return newServerError("Failed to create device code", err).respond(c)
+	}
+
+	verificationURIComplete, err := buildRedirectURL(h.config.OAuth.DeviceVerificationURL, map[string]string{
+		"user_code": formatUserCode(userCode),
+	})
+	if err != nil {
+		return newServerError("Failed to build verification URI", err).respond(c)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(int(utils.StatusCodeSuccess), DeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                formatUserCode(userCode),
+		VerificationURI:         h.config.OAuth.DeviceVerificationURL,
+		VerificationURIComplete: verificationURIComplete,
+		ExpiresIn:               int(expiry.Seconds()),
+		Interval:                interval,
+	})
+}
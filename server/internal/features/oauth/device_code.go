@@ -0,0 +1,56 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+// Device authorization request states
+const (
+	deviceCodeStatusPending  = "pending"
+	deviceCodeStatusApproved = "approved"
+	deviceCodeStatusDenied   = "denied"
+	deviceCodeStatusConsumed = "consumed"
+)
+
+// User codes use consonants only, so they are easy to type and cannot spell words (RFC 8628 section 6.1)
+const (
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+)
+
+// slowDownIncrement is added to the polling interval of a device polling too fast (RFC 8628 section 3.5)
+const slowDownIncrement = 5
+
+// generateUserCode creates a random user code in its normalized form
+func generateUserCode() (string, error) {
+	max := big.NewInt(int64(len(userCodeCharset)))
+
+	b := make([]byte, userCodeLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = userCodeCharset[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// normalizeUserCode strips the separator and casing users may type a user code with
+func normalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(userCode)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, userCode)
+}
+
+// formatUserCode splits a normalized user code in two halves for display, e.g. BCDF-GHJK
+func formatUserCode(userCode string) string {
+	half := len(userCode) / 2
+	return userCode[:half] + "-" + userCode[half:]
+}
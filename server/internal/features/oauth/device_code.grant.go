@@ -0,0 +1,104 @@
+package oauth
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// deviceCodeGrant exchanges an approved device code for tokens (RFC 8628 section 3.4). Until
+// the user has decided the device is told to keep polling at its interval.
+func (h *OAuthHandler) deviceCodeGrant(c echo.Context, req *TokenRequest, client sqlc.Client) error {
+	ctx := c.Request().Context()
+
+	if req.DeviceCode == "" {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidRequest, "device_code is required")
+	}
+
+	// Device codes are stored hashed
+	deviceCode, err := h.store.GetDeviceCodeByDeviceCode(ctx, utils.HashToken(req.DeviceCode))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Invalid device code")
+		}
+		return newServerError("Failed to fetch device code", err).respond(c)
+	}
+
+	if deviceCode.ClientID != client.ID {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Invalid device code")
+	}
+
+	if time.Now().After(deviceCode.ExpiresAt) {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorExpiredToken, "Device code has expired")
+	}
+
+	switch deviceCode.Status {
+	case deviceCodeStatusPending:
+		return h.respondToPendingPoll(c, deviceCode)
+	case deviceCodeStatusDenied:
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorAccessDenied, "The user denied the request")
+	case deviceCodeStatusApproved:
+		// Exchanged for tokens below
+	default:
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Device code has already been used")
+	}
+
+	// Mark the device code as used, only one concurrent exchange can win
+	updated, err := h.store.ConsumeDeviceCode(ctx, deviceCode.ID)
+	if err != nil {
+		return newServerError("Failed to mark device code as used", err).respond(c)
+	}
+	if updated == 0 {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Device code has already been used")
+	}
+
+	user, err := h.store.GetUserByID(ctx, deviceCode.UserID.UUID)
+	if err != nil {
+		return newServerError("Failed to fetch user", err).respond(c)
+	}
+	if user.Active.Valid && !user.Active.Bool {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "User account is not active")
+	}
+
+	res, err := h.issueUserTokens(ctx, h.store.Queries, userTokenGrant{
+		User:      user,
+		Client:    client,
+		Scope:     deviceCode.Scope,
+		AuthTime:  deviceCode.AuthTime,
+		SessionID: deviceCode.SessionID,
+	})
+	if err != nil {
+		return newServerError("Failed to issue tokens", err).respond(c)
+	}
+
+	return respondWithToken(c, res)
+}
+
+// respondToPendingPoll tells a device to keep polling. A device polling before its interval has
+// passed is told to slow down and its interval is increased for all following requests.
+func (h *OAuthHandler) respondToPendingPoll(c echo.Context, deviceCode sqlc.DeviceCode) error {
+	now := time.Now()
+	interval := deviceCode.PollInterval
+	tooFast := deviceCode.LastPolledAt.Valid &&
+		now.Sub(deviceCode.LastPolledAt.Time) < time.Duration(interval)*time.Second
+	if tooFast {
+		interval += slowDownIncrement
+	}
+
+	err := h.store.UpdateDeviceCodePoll(c.Request().Context(), sqlc.UpdateDeviceCodePollParams{
+		ID:           deviceCode.ID,
+		LastPolledAt: sql.NullTime{Time: now, Valid: true},
+		PollInterval: interval,
+	})
+	if err != nil {
+		return newServerError("Failed to record device poll", err).respond(c)
+	}
+
+	if tooFast {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorSlowDown, "Polling too frequently, increase the interval")
+	}
+	return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorAuthorizationPending, "The user has not yet approved the request")
+}
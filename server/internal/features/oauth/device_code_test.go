@@ -0,0 +1,55 @@
+package oauth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeUserCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		userCode string
+		want     string
+	}{
+		{"normalized", "BCDFGHJK", "BCDFGHJK"},
+		{"formatted", "BCDF-GHJK", "BCDFGHJK"},
+		{"lower case", "bcdf-ghjk", "BCDFGHJK"},
+		{"mixed case", "bCdF-gHjK", "BCDFGHJK"},
+		{"spaces", " BCDF GHJK ", "BCDFGHJK"},
+		{"several separators", "BC-DF - GH-JK", "BCDFGHJK"},
+		{"other characters are kept", "BCDF_GHJK", "BCDF_GHJK"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeUserCode(tt.userCode); got != tt.want {
+				t.Errorf("normalizeUserCode(%q) = %q, want %q", tt.userCode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatUserCode(t *testing.T) {
+	if got := formatUserCode("BCDFGHJK"); got != "BCDF-GHJK" {
+		t.Errorf("formatUserCode() = %q, want %q", got, "BCDF-GHJK")
+	}
+}
+
+func TestGenerateUserCode(t *testing.T) {
+	userCode, err := generateUserCode()
+	if err != nil {
+		t.Fatalf("generateUserCode() error = %v", err)
+	}
+	if len(userCode) != userCodeLength {
+		t.Errorf("generateUserCode() = %q, want %d characters", userCode, userCodeLength)
+	}
+	for _, r := range userCode {
+		if !strings.ContainsRune(userCodeCharset, r) {
+			t.Errorf("generateUserCode() = %q, contains %q outside the charset", userCode, r)
+		}
+	}
+	if got := normalizeUserCode(formatUserCode(userCode)); got != userCode {
+		t.Errorf("normalizeUserCode(formatUserCode(%q)) = %q", userCode, got)
+	}
+}
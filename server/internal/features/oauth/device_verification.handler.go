@@ -0,0 +1,198 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// pendingDeviceRequest is a device authorization request still waiting for the user's decision
+type pendingDeviceRequest struct {
+	DeviceCode sqlc.DeviceCode
+	Client     sqlc.Client
+	Scopes     []sqlc.Scope
+}
+
+// GetDeviceVerification describes the device authorization request behind a user code so the
+// verification page can ask the logged-in user for approval
+func (h *OAuthHandler) GetDeviceVerification(c echo.Context) error {
+	req := new(DeviceVerificationRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request",
+			err,
+		)
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	session, err := h.requireSession(c)
+	if session == nil {
+		return err
+	}
+
+	pending, err := h.getPendingDeviceRequest(ctx, req.UserCode)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch device authorization request", err)
+	}
+	if pending == nil {
+		return respondWithInvalidUserCode(c)
+	}
+
+	granted, err := h.grantedScopes(ctx, session.UserID, pending.Client.ID)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch consent", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Device authorization request retrieved successfully",
+		DeviceVerificationResponse{
+			UserCode:        formatUserCode(pending.DeviceCode.UserCode),
+			ConsentResponse: buildConsentResponse(deviceClientResponse(pending.Client), pending.Scopes, granted),
+		},
+	)
+}
+
+// SubmitDeviceVerification records the user's decision on a device authorization request. The
+// device learns about it the next time it polls the token endpoint.
+func (h *OAuthHandler) SubmitDeviceVerification(c echo.Context) error {
+	req := new(DeviceVerificationDecisionRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	session, err := h.requireSession(c)
+	if session == nil {
+		return err
+	}
+
+	pending, err := h.getPendingDeviceRequest(ctx, req.UserCode)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch device authorization request", err)
+	}
+	if pending == nil {
+		return respondWithInvalidUserCode(c)
+	}
+
+	if !req.Approve {
+		updated, err := h.store.DenyDeviceCode(ctx, pending.DeviceCode.ID)
+		if err != nil {
+			return utils.RespondWithInternalError(c, "Failed to deny device authorization request", err)
+		}
+		if updated == 0 {
+			return respondWithInvalidUserCode(c)
+		}
+		return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, "Device authorization request denied", nil)
+	}
+
+	user, err := h.store.GetUserByID(ctx, session.UserID)
+	if err != nil || (user.Active.Valid && !user.Active.Bool) {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeForbidden,
+			"Forbidden",
+			utils.ErrorCodeForbidden,
+			"User account is not active",
+			nil,
+		)
+	}
+
+	updated, err := h.store.ApproveDeviceCode(ctx, sqlc.ApproveDeviceCodeParams{
+		ID:        pending.DeviceCode.ID,
+		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		AuthTime:  session.CreatedAt,
+		SessionID: uuid.NullUUID{UUID: session.ID, Valid: true},
+	})
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to approve device authorization request", err)
+	}
+	if updated == 0 {
+		return respondWithInvalidUserCode(c)
+	}
+
+	// Approving a third-party device request is the user's consent to its scopes
+	if !pending.Client.IsFirstParty {
+		if err := h.rememberConsent(ctx, user.ID, pending.Client.ID, pending.DeviceCode.Scope); err != nil {
+			return utils.RespondWithInternalError(c, "Failed to save consent", err)
+		}
+	}
+
+	return utils.RespondWithSuccess(c, utils.StatusCodeSuccess, "Device authorization request approved", nil)
+}
+
+// getPendingDeviceRequest looks up the device authorization request of a user code. Requests that
+// are unknown, expired or already decided are returned as nil.
+func (h *OAuthHandler) getPendingDeviceRequest(ctx context.Context, userCode string) (*pendingDeviceRequest, error) {
+	deviceCode, err := h.store.GetDeviceCodeByUserCode(ctx, normalizeUserCode(userCode))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if deviceCode.Status != deviceCodeStatusPending || time.Now().After(deviceCode.ExpiresAt) {
+		return nil, nil
+	}
+
+	client, err := h.store.GetClientById(ctx, deviceCode.ClientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	scopes, err := h.store.GetScopesByNames(ctx, uniqueScopes(deviceCode.Scope))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pendingDeviceRequest{DeviceCode: deviceCode, Client: client, Scopes: scopes}, nil
+}
+
+func deviceClientResponse(client sqlc.Client) ConsentClientResponse {
+	return ConsentClientResponse{
+		ClientID:    client.ClientID,
+		Name:        client.Name,
+		Description: client.Description.String,
+		WebsiteURL:  client.WebsiteUrl.String,
+	}
+}
+
+// respondWithInvalidUserCode reports a user code that cannot be acted on, without revealing why
+func respondWithInvalidUserCode(c echo.Context) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeBadRequest,
+		"Invalid code",
+		utils.ErrorCodeInvalidRequest,
+		"The code is invalid, has expired or has already been used",
+		nil,
+	)
+}
@@ -0,0 +1,70 @@
+package oauth
+
+import (
+	"slices"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// OpenIDConfiguration handles the OpenID Connect discovery document (OpenID Connect Discovery 1.0)
+func (h *OAuthHandler) OpenIDConfiguration(c echo.Context) error {
+	issuer := h.config.JWT.Issuer
+
+	// Advertise the registered scopes, falling back to the OpenID Connect ones
+	scopesSupported := []string{scopeOpenID, scopeProfile, scopeEmail}
+	if scopes, err := h.store.ListScopes(c.Request().Context()); err == nil {
+		scopesSupported = make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			scopesSupported = append(scopesSupported, scope.Name)
+		}
+	}
+
+	// Introspection is limited to confidential clients
+	confidentialAuthMethods := slices.DeleteFunc(slices.Clone(supportedAuthMethods), func(method string) bool {
+		return method == authMethodNone
+	})
+	clientAssertionAlgorithms := slices.Concat(privateKeyJWTAlgorithms, clientSecretJWTAlgorithms)
+
+	res := OpenIDConfigurationResponse{
+		Issuer:                                     issuer,
+		AuthorizationEndpoint:                      issuer + "/oauth/authorize",
+		TokenEndpoint:                              issuer + "/oauth/token",
+		UserInfoEndpoint:                           issuer + "/oauth/userinfo",
+		RevocationEndpoint:                         issuer + "/oauth/revoke",
+		DeviceAuthorizationEndpoint:                issuer + "/oauth/device_authorization",
+		IntrospectionEndpoint:                      issuer + "/oauth/introspect",
+		EndSessionEndpoint:                         issuer + "/oauth/end_session",
+		JWKSURI:                                    issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:                     []string{"code"},
+		GrantTypesSupported:                        supportedGrantTypes,
+		SubjectTypesSupported:                      []string{"public"},
+		IDTokenSigningAlgValuesSupported:           []string{h.config.JWT.SigningAlgorithm},
+		ScopesSupported:                            scopesSupported,
+		TokenEndpointAuthMethodsSupported:          supportedAuthMethods,
+		TokenEndpointAuthSigningAlgValuesSupported: clientAssertionAlgorithms,
+		RevocationEndpointAuthMethodsSupported:     supportedAuthMethods,
+		IntrospectionEndpointAuthMethodsSupported:  confidentialAuthMethods,
+		CodeChallengeMethodsSupported:              []string{codeChallengeMethodS256, codeChallengeMethodPlain},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "at_hash", "sid", "amr",
+			"name", "birthdate", "updated_at", "email", "email_verified",
+		},
+		BackchannelLogoutSupported:        true,
+		BackchannelLogoutSessionSupported: true,
+	}
+
+	// Registration is only advertised while it is enabled
+	if h.config.OAuth.RegistrationToken != "" {
+		res.RegistrationEndpoint = issuer + "/oauth/register"
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=3600")
+	return c.JSON(int(utils.StatusCodeSuccess), res)
+}
+
+// JWKS publishes the public keys tokens are signed with, identified by their kid
+func (h *OAuthHandler) JWKS(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(int(utils.StatusCodeSuccess), utils.GetJWKS())
+}
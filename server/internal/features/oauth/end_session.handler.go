@@ -0,0 +1,162 @@
+package oauth
+
+import (
+	"database/sql"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// EndSession handles the OpenID Connect end session endpoint (RP-Initiated Logout 1.0). It logs
+// the user out of CentralAuth and of every client the session was used with, then sends them
+// to a post_logout_redirect_uri registered by the client or to the logout page. Requests
+// without an ID token are confirmed by the user first.
+func (h *OAuthHandler) EndSession(c echo.Context) error {
+	// Parse the query or form parameters
+	req := new(EndSessionRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request data",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse logout request",
+			err,
+		)
+	}
+
+	ctx := c.Request().Context()
+
+	// The ID token previously issued to the client identifies the client and the user,
+	// it may have expired in the meantime
+	clientID := req.ClientID
+	var hintSubject string
+	if req.IDTokenHint != "" {
+		hint, err := utils.ValidateIDTokenHint(req.IDTokenHint, clientID)
+		if err != nil {
+			return respondWithInvalidLogoutRequest(c, "id_token_hint is not a valid ID token issued by this server to the client")
+		}
+		if clientID == "" {
+			clientID = hint.Audience[0]
+		}
+		hintSubject = hint.Subject
+	}
+
+	// Users are only sent back to a URI the client registered for this purpose
+	redirectTo := h.config.OAuth.LogoutURL
+	if req.PostLogoutRedirectURI != "" {
+		if clientID == "" {
+			return respondWithInvalidLogoutRequest(c, "post_logout_redirect_uri requires id_token_hint or client_id")
+		}
+
+		client, err := h.store.GetClientByClientId(ctx, clientID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return respondWithInvalidLogoutRequest(c, "Unknown or inactive client_id")
+			}
+			return utils.RespondWithInternalError(c, "Failed to fetch client", err)
+		}
+		if !slices.Contains(client.PostLogoutRedirectUris, req.PostLogoutRedirectURI) {
+			return respondWithInvalidLogoutRequest(c, "post_logout_redirect_uri is not registered for this client")
+		}
+
+		redirectTo, err = buildRedirectURL(req.PostLogoutRedirectURI, map[string]string{
+			"state": req.State,
+		})
+		if err != nil {
+			return utils.RespondWithInternalError(c, "Failed to build redirect URL", err)
+		}
+	}
+
+	session, err := h.getSession(c)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to retrieve session", err)
+	}
+
+	// The browser is logged in as someone else than the client asks to log out, that
+	// session is left alone
+	if session != nil && hintSubject != "" && hintSubject != session.UserID.String() {
+		return c.Redirect(http.StatusFound, redirectTo)
+	}
+
+	// Without an ID token any site could have sent the user here (RP-Initiated Logout section 2),
+	// they confirm the logout first. The confirmation page posts the request back, browsers only
+	// send the Lax session cookie along with form posts from the same site.
+	if session != nil && hintSubject == "" && !(c.Request().Method == http.MethodPost && req.Confirm) {
+		return h.redirectToLogoutConfirmation(c, req)
+	}
+
+	// End the session of the browser along with the client sessions it was used for
+	if session != nil {
+		if err := h.logoutNotifier.SessionsEnded(ctx, session.UserID, []uuid.UUID{session.ID}); err != nil {
+			return utils.RespondWithInternalError(c, "Failed to end client sessions", err)
+		}
+		if err := h.store.DeactivateSession(ctx, session.SessionToken); err != nil {
+			return utils.RespondWithInternalError(c, "Failed to deactivate session", err)
+		}
+	}
+
+	// Clear the session token cookie
+	c.SetCookie(&http.Cookie{
+		Name:     "session_token",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1, // This deletes the cookie
+	})
+
+	// Clear the access token cookie
+	c.SetCookie(&http.Cookie{
+		Name:     "access_token",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1, // This deletes the cookie
+	})
+
+	return c.Redirect(http.StatusFound, redirectTo)
+}
+
+// redirectToLogoutConfirmation sends the user to the page confirming a logout request, along
+// with the parameters to post back
+func (h *OAuthHandler) redirectToLogoutConfirmation(c echo.Context, req *EndSessionRequest) error {
+	confirmURL, err := url.Parse(h.config.OAuth.LogoutConfirmURL)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to build logout confirmation URL", err)
+	}
+
+	query := confirmURL.Query()
+	for name, value := range map[string]string{
+		"client_id":                req.ClientID,
+		"post_logout_redirect_uri": req.PostLogoutRedirectURI,
+		"state":                    req.State,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	confirmURL.RawQuery = query.Encode()
+
+	return c.Redirect(http.StatusFound, confirmURL.String())
+}
+
+// respondWithInvalidLogoutRequest shows an error to the user, a logout request that cannot be
+// verified must not redirect anywhere
+func respondWithInvalidLogoutRequest(c echo.Context, description string) error {
+	return utils.RespondWithError(
+		c,
+		utils.StatusCodeBadRequest,
+		"Invalid logout request",
+		utils.ErrorCodeInvalidRequest,
+		description,
+		nil,
+	)
+}
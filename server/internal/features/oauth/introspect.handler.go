@@ -0,0 +1,200 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Introspect handles the OAuth 2.0 token introspection endpoint (RFC 7662). Only confidential
+// clients may introspect tokens. Any of them may introspect access tokens, since resource servers
+// check the tokens presented to them, while refresh tokens are only reported to the client they
+// were issued to. Other tokens are reported as inactive, which tells nothing about them
+// (RFC 7662 section 2.2).
+func (h *OAuthHandler) Introspect(c echo.Context) error {
+	req := new(IntrospectRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorInvalidRequest,
+			"Could not parse introspection request",
+		)
+	}
+
+	client, oauthErr := h.authenticateClient(c, req.ClientCredentials)
+	if oauthErr != nil {
+		return oauthErr.respond(c)
+	}
+	if !isConfidential(client) {
+		return newOAuthError(utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidClient, "Public clients cannot introspect tokens").respond(c)
+	}
+
+	if req.Token == "" {
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorInvalidRequest,
+			"token is required",
+		)
+	}
+
+	// Access tokens are JWTs and refresh tokens are opaque, so the token itself tells which
+	// lookup applies and the hint is not needed
+	var res IntrospectResponse
+	var err error
+	if claims, validateErr := utils.ValidateToken(req.Token); validateErr == nil {
+		res, err = h.introspectAccessToken(c.Request().Context(), claims)
+	} else {
+		res, err = h.introspectRefreshToken(c.Request().Context(), client, req.Token)
+	}
+	if err != nil {
+		return newServerError("Failed to introspect token", err).respond(c)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	c.Response().Header().Set("Pragma", "no-cache")
+	return c.JSON(int(utils.StatusCodeSuccess), res)
+}
+
+// introspectAccessToken reports a validated access token as active unless it was revoked or
+// the user, session or client it was issued to is no longer active
+func (h *OAuthHandler) introspectAccessToken(ctx context.Context, claims *utils.AccessTokenClaims) (IntrospectResponse, error) {
+	inactive := IntrospectResponse{Active: false}
+
+	revoked, err := h.store.IsAccessTokenRevoked(ctx, claims.ID)
+	if err != nil || revoked {
+		return inactive, err
+	}
+
+	// First-party tokens carry no client
+	if claims.ClientID != "" {
+		ok, err := h.isClientActive(ctx, claims.ClientID)
+		if err != nil || !ok {
+			return inactive, err
+		}
+	}
+
+	username := ""
+	if !claims.IsClientToken() {
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			return inactive, nil
+		}
+		user, err := h.store.GetUserByID(ctx, userID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && user.Active.Valid && !user.Active.Bool) {
+			return inactive, nil
+		}
+		if err != nil {
+			return inactive, err
+		}
+		// The email address is only shared with clients it was granted to
+		if hasScope(claims.Scope, scopeEmail) {
+			username = user.Email
+		}
+
+		// Tokens issued to a login session end with the session
+		if claims.SessionID != "" {
+			sessionID, err := uuid.Parse(claims.SessionID)
+			if err != nil {
+				return inactive, nil
+			}
+			if _, err := h.store.GetSessionByID(ctx, sessionID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return inactive, nil
+				}
+				return inactive, err
+			}
+		}
+	}
+
+	res := IntrospectResponse{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Username:  username,
+		TokenType: "Bearer",
+		Subject:   claims.Subject,
+		Audience:  claims.Audience,
+		Issuer:    claims.Issuer,
+		JTI:       claims.ID,
+	}
+	if res.Subject == "" {
+		res.Subject = claims.UserID
+	}
+	if claims.ExpiresAt != nil {
+		res.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		res.IssuedAt = claims.IssuedAt.Unix()
+	}
+	return res, nil
+}
+
+// introspectRefreshToken reports an opaque refresh token issued to client as active while it
+// can still be exchanged at the token endpoint
+func (h *OAuthHandler) introspectRefreshToken(ctx context.Context, client sqlc.Client, token string) (IntrospectResponse, error) {
+	inactive := IntrospectResponse{Active: false}
+
+	refreshToken, err := h.store.GetRefreshTokenByToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return inactive, nil
+		}
+		return inactive, err
+	}
+	if refreshToken.ClientID != client.ID {
+		return inactive, nil
+	}
+	if (refreshToken.IsActive.Valid && !refreshToken.IsActive.Bool) || time.Now().After(refreshToken.ExpiresAt) {
+		return inactive, nil
+	}
+
+	user, err := h.store.GetUserByID(ctx, refreshToken.UserID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && user.Active.Valid && !user.Active.Bool) {
+		return inactive, nil
+	}
+	if err != nil {
+		return inactive, err
+	}
+
+	if client.IsActive.Valid && !client.IsActive.Bool {
+		return inactive, nil
+	}
+
+	res := IntrospectResponse{
+		Active:    true,
+		Scope:     refreshToken.Scope,
+		ClientID:  client.ClientID,
+		TokenType: tokenTypeHintRefreshToken,
+		ExpiresAt: refreshToken.ExpiresAt.Unix(),
+		Subject:   user.ID.String(),
+		Issuer:    h.config.JWT.Issuer,
+	}
+	// The email address is only shared with clients it was granted to
+	if hasScope(refreshToken.Scope, scopeEmail) {
+		res.Username = user.Email
+	}
+	if refreshToken.CreatedAt.Valid {
+		res.IssuedAt = refreshToken.CreatedAt.Time.Unix()
+	}
+	return res, nil
+}
+
+// isClientActive reports whether the client with the given client_id exists and is active
+func (h *OAuthHandler) isClientActive(ctx context.Context, clientID string) (bool, error) {
+	client, err := h.store.GetClientByClientId(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !client.IsActive.Valid || client.IsActive.Bool, nil
+}
@@ -0,0 +1,89 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+)
+
+// errClientKeysUnavailable is returned when the keys published at a jwks_uri cannot be fetched
+var errClientKeysUnavailable = errors.New("client keys unavailable")
+
+const (
+	jwksCacheTTL        = 5 * time.Minute  // How long fetched key sets are reused
+	jwksMinRefresh      = 30 * time.Second // Minimum time between forced refreshes of a key set
+	jwksMaxResponseSize = 1 << 20          // Largest key set document that is read
+)
+
+type jwksCacheEntry struct {
+	jwks      utils.JWKS
+	fetchedAt time.Time
+}
+
+// jwksCache fetches and caches the key sets clients publish at their jwks_uri
+type jwksCache struct {
+	mu      sync.Mutex
+	entries map[string]jwksCacheEntry
+	client  *http.Client
+}
+
+func newJWKSCache() *jwksCache {
+	return &jwksCache{
+		entries: make(map[string]jwksCacheEntry),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// get returns the key set published at uri. A refresh fetches it again unless it was
+// fetched very recently, which limits how often a client can trigger outgoing requests.
+func (c *jwksCache) get(ctx context.Context, uri string, refresh bool) (utils.JWKS, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[uri]
+	c.mu.Unlock()
+
+	age := time.Since(entry.fetchedAt)
+	if ok && age < jwksCacheTTL && (!refresh || age < jwksMinRefresh) {
+		return entry.jwks, nil
+	}
+
+	jwks, err := c.fetch(ctx, uri)
+	if err != nil {
+		return utils.JWKS{}, fmt.Errorf("%w: %v", errClientKeysUnavailable, err)
+	}
+
+	c.mu.Lock()
+	c.entries[uri] = jwksCacheEntry{jwks: jwks, fetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	return jwks, nil
+}
+
+func (c *jwksCache) fetch(ctx context.Context, uri string) (utils.JWKS, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return utils.JWKS{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return utils.JWKS{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return utils.JWKS{}, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, jwksMaxResponseSize))
+	if err != nil {
+		return utils.JWKS{}, err
+	}
+	return utils.ParseJWKS(body)
+}
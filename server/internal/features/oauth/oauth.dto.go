@@ -0,0 +1,201 @@
+package oauth
+
+import "time"
+
+// ==========
+// OAuth DTOs
+// ==========
+
+// === Authorize Dto ===
+type AuthorizeRequest struct {
+	ResponseType        string `json:"response_type" query:"response_type" validate:"required"`
+	ClientID            string `json:"client_id" query:"client_id" validate:"required,max=50"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri" validate:"omitempty,url"`
+	Scope               string `json:"scope" query:"scope" validate:"max=1000"`
+	State               string `json:"state" query:"state" validate:"max=512"`
+	Nonce               string `json:"nonce" query:"nonce" validate:"max=512"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method"`
+}
+
+// === Consent Dto ===
+// The consent page submits the authorization request it was sent with along with the decision
+type ConsentRequest struct {
+	AuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+type ConsentClientResponse struct {
+	ClientID    string `json:"client_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	WebsiteURL  string `json:"website_url"`
+}
+
+type ConsentScopeResponse struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	RequiresConsent bool   `json:"requires_consent"`
+	Granted         bool   `json:"granted"` // Already granted to the client before
+}
+
+type ConsentResponse struct {
+	Client ConsentClientResponse  `json:"client"`
+	Scopes []ConsentScopeResponse `json:"scopes"`
+}
+
+type ConsentDecisionResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// === Authorized Apps Dto ===
+type AuthorizedAppResponse struct {
+	ClientID    string    `json:"client_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	WebsiteURL  string    `json:"website_url"`
+	Scopes      []string  `json:"scopes"`
+	GrantedAt   time.Time `json:"granted_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// === Token Dto ===
+// Token requests are form encoded (RFC 6749 section 4.1.3) and validated by the grant
+// handlers so that errors can be reported in the OAuth error format
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	DeviceCode   string `form:"device_code"`
+	Scope        string `form:"scope"`
+	ClientCredentials
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// === Client Credentials Dto ===
+// Client authentication parameters of the back-channel endpoints, either a client secret
+// (RFC 6749 section 2.3.1) or a JWT assertion (RFC 7523 section 2.2)
+type ClientCredentials struct {
+	ClientID            string `form:"client_id"`
+	ClientSecret        string `form:"client_secret"`
+	ClientAssertionType string `form:"client_assertion_type"`
+	ClientAssertion     string `form:"client_assertion"`
+}
+
+// === Device Authorization Dto ===
+type DeviceAuthorizationRequest struct {
+	Scope string `form:"scope"`
+	ClientCredentials
+}
+
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// === Device Verification Dto ===
+type DeviceVerificationRequest struct {
+	UserCode string `json:"user_code" query:"user_code" validate:"required,max=16"`
+}
+
+type DeviceVerificationDecisionRequest struct {
+	UserCode string `json:"user_code" validate:"required,max=16"`
+	Approve  bool   `json:"approve"`
+}
+
+type DeviceVerificationResponse struct {
+	UserCode string `json:"user_code"`
+	ConsentResponse
+}
+
+// === End Session Dto ===
+// Parameters of OpenID Connect RP-Initiated Logout 1.0 section 2, sent as query or form parameters
+type EndSessionRequest struct {
+	IDTokenHint           string `query:"id_token_hint" form:"id_token_hint"`
+	ClientID              string `query:"client_id" form:"client_id"`
+	PostLogoutRedirectURI string `query:"post_logout_redirect_uri" form:"post_logout_redirect_uri"`
+	State                 string `query:"state" form:"state"`
+	// Set by the confirmation page when the user confirmed a logout without id_token_hint
+	Confirm bool `form:"confirm"`
+}
+
+// === Revoke Dto ===
+type RevokeRequest struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientCredentials
+}
+
+// === Introspect Dto ===
+type IntrospectRequest struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientCredentials
+}
+
+// Only active is set for tokens that are not active (RFC 7662 section 2.2)
+type IntrospectResponse struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	JTI       string   `json:"jti,omitempty"`
+}
+
+// === UserInfo Dto ===
+// Claims are included according to the scopes granted to the access token
+type UserInfoResponse struct {
+	Subject       string `json:"sub"`
+	Name          string `json:"name,omitempty"`
+	Birthdate     string `json:"birthdate,omitempty"`
+	UpdatedAt     int64  `json:"updated_at,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+}
+
+// === Discovery Dto ===
+type OpenIDConfigurationResponse struct {
+	Issuer                                     string   `json:"issuer"`
+	AuthorizationEndpoint                      string   `json:"authorization_endpoint"`
+	TokenEndpoint                              string   `json:"token_endpoint"`
+	UserInfoEndpoint                           string   `json:"userinfo_endpoint"`
+	RevocationEndpoint                         string   `json:"revocation_endpoint"`
+	DeviceAuthorizationEndpoint                string   `json:"device_authorization_endpoint"`
+	RegistrationEndpoint                       string   `json:"registration_endpoint,omitempty"`
+	IntrospectionEndpoint                      string   `json:"introspection_endpoint"`
+	EndSessionEndpoint                         string   `json:"end_session_endpoint"`
+	JWKSURI                                    string   `json:"jwks_uri"`
+	ResponseTypesSupported                     []string `json:"response_types_supported"`
+	GrantTypesSupported                        []string `json:"grant_types_supported"`
+	SubjectTypesSupported                      []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported           []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                            []string `json:"scopes_supported"`
+	TokenEndpointAuthMethodsSupported          []string `json:"token_endpoint_auth_methods_supported"`
+	TokenEndpointAuthSigningAlgValuesSupported []string `json:"token_endpoint_auth_signing_alg_values_supported"`
+	RevocationEndpointAuthMethodsSupported     []string `json:"revocation_endpoint_auth_methods_supported"`
+	IntrospectionEndpointAuthMethodsSupported  []string `json:"introspection_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported              []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                            []string `json:"claims_supported"`
+	BackchannelLogoutSupported                 bool     `json:"backchannel_logout_supported"`
+	BackchannelLogoutSessionSupported          bool     `json:"backchannel_logout_session_supported"`
+}
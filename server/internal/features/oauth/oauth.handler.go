@@ -0,0 +1,73 @@
+package oauth
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type OAuthHandler struct {
+	store          *db.Store
+	config         *config.Config
+	jwksCache      *jwksCache      // Key sets of clients authenticating with private_key_jwt
+	logoutNotifier *LogoutNotifier // Ends the client sessions of logged out login sessions
+}
+
+// NewOAuthHandler creates a new OAuth 2.0 authorization server handler
+func NewOAuthHandler(ah *features.AppHandlers) *OAuthHandler {
+	return &OAuthHandler{
+		store:          ah.Store,
+		config:         ah.Cfg,
+		jwksCache:      newJWKSCache(),
+		logoutNotifier: NewLogoutNotifier(ah.Store),
+	}
+}
+
+// getSession returns the active session of the logged-in user, or nil if the request
+// carries no valid session cookie
+func (h *OAuthHandler) getSession(c echo.Context) (*sqlc.Session, error) {
+	sessionCookie, err := c.Cookie("session_token")
+	if err != nil || sessionCookie.Value == "" {
+		return nil, nil
+	}
+
+	session, err := h.store.GetSessionByToken(c.Request().Context(), sessionCookie.Value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// oauthError is an RFC 6749 error produced by a helper and sent by the calling handler
+type oauthError struct {
+	status      utils.StatusCode
+	code        utils.OAuthErrorCode
+	description string
+}
+
+func newOAuthError(status utils.StatusCode, code utils.OAuthErrorCode, description string) *oauthError {
+	return &oauthError{status: status, code: code, description: description}
+}
+
+// newServerError logs the underlying error and hides it from the client
+func newServerError(message string, err error) *oauthError {
+	log.Printf("OAUTH SERVER ERROR: %v - %v", message, err)
+	return newOAuthError(utils.StatusCodeInternalError, utils.OAuthErrorServerError, "An unexpected error occurred while processing your request")
+}
+
+// respond sends the error to the client
+func (e *oauthError) respond(c echo.Context) error {
+	if e.code == utils.OAuthErrorInvalidClient {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+	}
+	return utils.RespondWithOAuthError(c, e.status, e.code, e.description)
+}
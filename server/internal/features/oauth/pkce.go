@@ -0,0 +1,48 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// PKCE code challenge methods (RFC 7636)
+const (
+	codeChallengeMethodS256  = "S256"
+	codeChallengeMethodPlain = "plain"
+)
+
+// isValidPKCEValue reports whether s is a well formed code verifier or code challenge
+func isValidPKCEValue(s string) bool {
+	if len(s) < 43 || len(s) > 128 {
+		return false
+	}
+	for _, r := range s {
+		isUnreserved := (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') ||
+			r == '-' || r == '.' || r == '_' || r == '~'
+		if !isUnreserved {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyCodeChallenge checks a code verifier against the challenge stored with the authorization code
+func verifyCodeChallenge(challenge, method, verifier string) bool {
+	if !isValidPKCEValue(verifier) {
+		return false
+	}
+
+	var computed string
+	switch method {
+	case codeChallengeMethodS256:
+		sum := sha256.Sum256([]byte(verifier))
+		computed = base64.RawURLEncoding.EncodeToString(sum[:])
+	case codeChallengeMethodPlain:
+		computed = verifier
+	default:
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
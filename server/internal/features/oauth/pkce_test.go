@@ -0,0 +1,44 @@
+package oauth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	const (
+		verifier  = "dBjftJeZ4CVP-mJ92IxQQ5jfa2sVcfbAV2UfRGCH7C8"
+		challenge = "u8IIHBF2DBY38OtucR-QWuqt1fXEK_9DqzHoRZZjqmo" // BASE64URL(SHA256(verifier))
+	)
+
+	tests := []struct {
+		name      string
+		challenge string
+		method    string
+		verifier  string
+		want      bool
+	}{
+		{"S256 matching verifier", challenge, codeChallengeMethodS256, verifier, true},
+		{"S256 other verifier", challenge, codeChallengeMethodS256, strings.Replace(verifier, "d", "e", 1), false},
+		{"S256 challenge is the verifier", verifier, codeChallengeMethodS256, verifier, false},
+		{"S256 challenge with padding", challenge + "=", codeChallengeMethodS256, verifier, false},
+		{"plain matching verifier", verifier, codeChallengeMethodPlain, verifier, true},
+		{"plain hashed challenge", challenge, codeChallengeMethodPlain, verifier, false},
+		{"unknown method", verifier, "S512", verifier, false},
+		{"method is case sensitive", challenge, "s256", verifier, false},
+		{"empty verifier", challenge, codeChallengeMethodS256, "", false},
+		{"verifier of 42 characters", verifier[:42], codeChallengeMethodPlain, verifier[:42], false},
+		{"verifier of 128 characters", strings.Repeat("a", 128), codeChallengeMethodPlain, strings.Repeat("a", 128), true},
+		{"verifier of 129 characters", strings.Repeat("a", 129), codeChallengeMethodPlain, strings.Repeat("a", 129), false},
+		{"verifier with a reserved character", verifier[:42] + "+", codeChallengeMethodPlain, verifier[:42] + "+", false},
+		{"verifier with unreserved symbols", strings.Repeat("-._~", 11), codeChallengeMethodPlain, strings.Repeat("-._~", 11), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCodeChallenge(tt.challenge, tt.method, tt.verifier); got != tt.want {
+				t.Errorf("verifyCodeChallenge(%q, %q, %q) = %v, want %v", tt.challenge, tt.method, tt.verifier, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package oauth
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+var errRefreshTokenReused = errors.New("refresh token has already been rotated")
+
+// refreshTokenGrant exchanges a refresh token for new tokens (RFC 6749 section 6). The presented
+// token is rotated on every use, presenting an already rotated token revokes its whole family.
+func (h *OAuthHandler) refreshTokenGrant(c echo.Context, req *TokenRequest, client sqlc.Client) error {
+	ctx := c.Request().Context()
+
+	if req.RefreshToken == "" {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidRequest, "refresh_token is required")
+	}
+
+	// Refresh tokens are stored hashed
+	refreshToken, err := h.store.GetRefreshTokenByToken(ctx, utils.HashToken(req.RefreshToken))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Invalid refresh token")
+		}
+		return newServerError("Failed to fetch refresh token", err).respond(c)
+	}
+
+	if refreshToken.ClientID != client.ID {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Invalid refresh token")
+	}
+
+	if refreshToken.RotatedAt.Valid {
+		return h.rejectReusedRefreshToken(c, refreshToken)
+	}
+
+	if refreshToken.IsActive.Valid && !refreshToken.IsActive.Bool {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Refresh token has been revoked")
+	}
+
+	if time.Now().After(refreshToken.ExpiresAt) {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Refresh token has expired")
+	}
+
+	// The client may ask for fewer scopes than were originally granted, never more
+	scope := refreshToken.Scope
+	if req.Scope != "" {
+		if !isScopeSubset(req.Scope, refreshToken.Scope) {
+			return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidScope, "Requested scope exceeds the originally granted scope")
+		}
+		scope = normalizeScope(req.Scope)
+	}
+
+	user, err := h.store.GetUserByID(ctx, refreshToken.UserID)
+	if err != nil {
+		return newServerError("Failed to fetch user", err).respond(c)
+	}
+	if user.Active.Valid && !user.Active.Bool {
+		return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "User account is not active")
+	}
+
+	// Rotate the presented token and store its successor together, a failure in between would
+	// otherwise leave the client without a usable token. A concurrent request rotating it first
+	// means it was reused.
+	var res *TokenResponse
+	err = h.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		rotated, err := q.RotateRefreshToken(ctx, refreshToken.ID)
+		if err != nil {
+			return err
+		}
+		if rotated == 0 {
+			return errRefreshTokenReused
+		}
+
+		res, err = h.issueUserTokens(ctx, q, userTokenGrant{
+			User:                user,
+			Client:              client,
+			Scope:               scope,
+			GrantedScope:        refreshToken.Scope,
+			FamilyID:            refreshToken.FamilyID,
+			AuthorizationCodeID: refreshToken.AuthorizationCodeID,
+			AuthTime:            refreshToken.AuthTime,
+			SessionID:           refreshToken.SessionID,
+		})
+		return err
+	})
+	if errors.Is(err, errRefreshTokenReused) {
+		return h.rejectReusedRefreshToken(c, refreshToken)
+	}
+	if err != nil {
+		return newServerError("Failed to issue tokens", err).respond(c)
+	}
+
+	return respondWithToken(c, res)
+}
+
+// rejectReusedRefreshToken revokes every token of the family when a rotated refresh token is
+// presented again, since either the legitimate client or an attacker holds a stolen copy
+func (h *OAuthHandler) rejectReusedRefreshToken(c echo.Context, refreshToken sqlc.RefreshToken) error {
+	log.Printf("Refresh token %s was reused, revoking token family %s", refreshToken.ID, refreshToken.FamilyID)
+
+	if err := h.store.DeactivateRefreshTokenFamily(c.Request().Context(), refreshToken.FamilyID); err != nil {
+		return newServerError("Failed to revoke refresh token family", err).respond(c)
+	}
+
+	return utils.RespondWithOAuthError(c, utils.StatusCodeBadRequest, utils.OAuthErrorInvalidGrant, "Refresh token has already been used")
+}
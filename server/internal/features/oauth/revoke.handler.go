@@ -0,0 +1,123 @@
+package oauth
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// Token type hints (RFC 7009 section 2.1)
+const (
+	tokenTypeHintAccessToken  = "access_token"
+	tokenTypeHintRefreshToken = "refresh_token"
+)
+
+// Revoke handles the OAuth 2.0 token revocation endpoint (RFC 7009). Clients authenticate
+// like at the token endpoint and may only revoke tokens issued to them. The response does
+// not reveal whether the token was valid.
+func (h *OAuthHandler) Revoke(c echo.Context) error {
+	req := new(RevokeRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorInvalidRequest,
+			"Could not parse revocation request",
+		)
+	}
+
+	client, oauthErr := h.authenticateClient(c, req.ClientCredentials)
+	if oauthErr != nil {
+		return oauthErr.respond(c)
+	}
+
+	if req.Token == "" {
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorInvalidRequest,
+			"token is required",
+		)
+	}
+
+	// The hint only decides which lookup comes first, unknown hints are ignored
+	revokers := []func(echo.Context, string, sqlc.Client) (bool, *oauthError){
+		h.revokeRefreshToken,
+		h.revokeAccessToken,
+	}
+	if req.TokenTypeHint == tokenTypeHintAccessToken {
+		revokers[0], revokers[1] = revokers[1], revokers[0]
+	}
+
+	for _, revoke := range revokers {
+		found, oauthErr := revoke(c, req.Token, client)
+		if oauthErr != nil {
+			return oauthErr.respond(c)
+		}
+		if found {
+			break
+		}
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.NoContent(int(utils.StatusCodeSuccess))
+}
+
+// revokeRefreshToken deactivates the refresh token and every token rotated from the same
+// grant. It reports whether the token was a refresh token.
+func (h *OAuthHandler) revokeRefreshToken(c echo.Context, token string, client sqlc.Client) (bool, *oauthError) {
+	ctx := c.Request().Context()
+
+	refreshToken, err := h.store.GetRefreshTokenByToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, newServerError("Failed to fetch refresh token", err)
+	}
+
+	// Tokens of other clients are left alone without telling the caller
+	if refreshToken.ClientID != client.ID {
+		return true, nil
+	}
+
+	if err := h.store.DeactivateRefreshTokenFamily(ctx, refreshToken.FamilyID); err != nil {
+		return false, newServerError("Failed to revoke refresh token", err)
+	}
+	return true, nil
+}
+
+// revokeAccessToken adds the access token to the denylist until it expires. It reports
+// whether the token was a valid access token.
+func (h *OAuthHandler) revokeAccessToken(c echo.Context, token string, client sqlc.Client) (bool, *oauthError) {
+	ctx := c.Request().Context()
+
+	// Expired or malformed tokens are already unusable
+	claims, err := utils.ValidateToken(token)
+	if err != nil {
+		return false, nil
+	}
+
+	// Tokens issued before jti was added expire on their own
+	if claims.ClientID != client.ClientID || claims.ID == "" || claims.ExpiresAt == nil {
+		return true, nil
+	}
+
+	err = h.store.RevokeAccessToken(ctx, sqlc.RevokeAccessTokenParams{
+		Jti:       claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return false, newServerError("Failed to revoke access token", err)
+	}
+
+	// Entries are only needed until the tokens expire
+	if _, err := h.store.DeleteExpiredRevokedTokens(ctx); err != nil {
+		log.Printf("Failed to delete expired revoked tokens: %v", err)
+	}
+	return true, nil
+}
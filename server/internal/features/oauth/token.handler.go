@@ -0,0 +1,208 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Supported grant types
+const (
+	grantTypeAuthorizationCode = "authorization_code"
+	grantTypeRefreshToken      = "refresh_token"
+	grantTypeClientCredentials = "client_credentials"
+	grantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code" // RFC 8628
+)
+
+var supportedGrantTypes = []string{
+	grantTypeAuthorizationCode,
+	grantTypeRefreshToken,
+	grantTypeClientCredentials,
+	grantTypeDeviceCode,
+}
+
+// Token handles the OAuth 2.0 token endpoint
+func (h *OAuthHandler) Token(c echo.Context) error {
+	// Parse the form encoded request body
+	req := new(TokenRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorInvalidRequest,
+			"Could not parse token request",
+		)
+	}
+
+	// Every grant requires the client to be identified
+	client, oauthErr := h.authenticateClient(c, req.ClientCredentials)
+	if oauthErr != nil {
+		return oauthErr.respond(c)
+	}
+
+	// The client must be registered for the grant type it uses
+	if slices.Contains(supportedGrantTypes, req.GrantType) && !slices.Contains(client.GrantTypes, req.GrantType) {
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorUnauthorizedClient,
+			"The client is not allowed to use this grant type",
+		)
+	}
+
+	switch req.GrantType {
+	case grantTypeAuthorizationCode:
+		return h.authorizationCodeGrant(c, req, client)
+	case grantTypeRefreshToken:
+		return h.refreshTokenGrant(c, req, client)
+	case grantTypeClientCredentials:
+		return h.clientCredentialsGrant(c, req, client)
+	case grantTypeDeviceCode:
+		return h.deviceCodeGrant(c, req, client)
+	case "":
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorInvalidRequest,
+			"grant_type is required",
+		)
+	default:
+		return utils.RespondWithOAuthError(
+			c,
+			utils.StatusCodeBadRequest,
+			utils.OAuthErrorUnsupportedGrantType,
+			"The grant type is not supported",
+		)
+	}
+}
+
+// userTokenGrant describes the tokens to issue for a user acting through a client
+type userTokenGrant struct {
+	User                sqlc.User
+	Client              sqlc.Client
+	Scope               string        // Scope of the access token
+	GrantedScope        string        // Scope carried by the refresh token, defaults to Scope
+	FamilyID            uuid.UUID     // Refresh token family, a new family is started when empty
+	AuthorizationCodeID uuid.NullUUID // Code the family was issued from
+	Nonce               string        // Nonce of the authorization request, echoed in the ID token
+	AuthTime            sql.NullTime  // When the user authenticated
+	SessionID           uuid.NullUUID // Login session the user authenticated with, logging it out ends the grant
+	AccessTokenID       string        // jti of the access token, generated when empty
+}
+
+// issueUserTokens creates an access token and a refresh token for a user acting through a client.
+// The refresh token is stored with q, which may be a transaction.
+func (h *OAuthHandler) issueUserTokens(ctx context.Context, q *sqlc.Queries, grant userTokenGrant) (*TokenResponse, error) {
+	user, client := grant.User, grant.Client
+	if grant.GrantedScope == "" {
+		grant.GrantedScope = grant.Scope
+	}
+	if grant.FamilyID == uuid.Nil {
+		grant.FamilyID = uuid.New()
+	}
+	grant.Scope = h.restrictUnverifiedScope(user, grant.Scope)
+
+	// The tokens name the methods the user logged in with, a session that has ended since
+	// no longer vouches for them
+	var amr []string
+	if grant.SessionID.Valid {
+		session, err := q.GetSessionByID(ctx, grant.SessionID.UUID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		amr = session.Amr
+	}
+
+	// The access token only names the user, the client learns about the user from the ID
+	// token and the userinfo endpoint as far as the scopes allow
+	claims := utils.AccessTokenClaims{
+		ClientID: client.ClientID,
+		Scope:    grant.Scope,
+		AMR:      amr,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:      grant.AccessTokenID,
+			Subject: user.ID.String(),
+		},
+	}
+	// Naming the session lets introspection report the token inactive once the session ends
+	if grant.SessionID.Valid {
+		claims.SessionID = grant.SessionID.UUID.String()
+	}
+
+	accessToken, expiresIn, err := utils.CreateAccessToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the refresh token, only its hash is stored
+	refreshToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
+		UserID:              user.ID,
+		ClientID:            client.ID,
+		Token:               utils.HashToken(refreshToken),
+		ExpiresAt:           time.Now().Add(time.Duration(h.config.JWT.RefreshExpiryHours) * time.Hour),
+		AuthorizationCodeID: grant.AuthorizationCodeID,
+		FamilyID:            grant.FamilyID,
+		Scope:               grant.GrantedScope,
+		AuthTime:            grant.AuthTime,
+		SessionID:           grant.SessionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    expiresIn,
+		RefreshToken: refreshToken,
+		Scope:        grant.Scope,
+	}
+
+	// OpenID Connect clients additionally get an ID token
+	if hasScope(grant.Scope, scopeOpenID) {
+		userInfo := buildUserInfo(user, grant.Scope)
+		idClaims := utils.IDTokenClaims{
+			Nonce:         grant.Nonce,
+			Name:          userInfo.Name,
+			Email:         userInfo.Email,
+			EmailVerified: userInfo.EmailVerified,
+			AMR:           amr,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:  user.ID.String(),
+				Audience: jwt.ClaimStrings{client.ClientID},
+			},
+		}
+		if grant.AuthTime.Valid {
+			idClaims.AuthTime = grant.AuthTime.Time.Unix()
+		}
+		if grant.SessionID.Valid {
+			idClaims.SessionID = grant.SessionID.UUID.String()
+		}
+
+		res.IDToken, err = utils.CreateIDToken(idClaims, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// respondWithToken sends a successful token response, which must never be cached
+func respondWithToken(c echo.Context, res *TokenResponse) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	c.Response().Header().Set("Pragma", "no-cache")
+	return c.JSON(int(utils.StatusCodeSuccess), res)
+}
@@ -0,0 +1,77 @@
+package oauth
+
+import (
+	"strings"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// UserInfo handles the OpenID Connect userinfo endpoint. It returns the claims of the user
+// an access token was issued to, filtered by the scopes granted to that token.
+func (h *OAuthHandler) UserInfo(c echo.Context) error {
+	// The access token must be sent as a bearer token (RFC 6750)
+	authHeader := c.Request().Header.Get(echo.HeaderAuthorization)
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="userinfo"`)
+		return utils.RespondWithOAuthError(c, utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidRequest, "Missing bearer token")
+	}
+
+	claims, err := utils.ValidateToken(strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil || claims.IsClientToken() || !hasScope(claims.Scope, scopeOpenID) {
+		return respondWithInvalidToken(c, "The access token is invalid or was not issued for OpenID Connect")
+	}
+
+	revoked, err := h.store.IsAccessTokenRevoked(c.Request().Context(), claims.ID)
+	if err != nil {
+		return newServerError("Failed to check token revocation", err).respond(c)
+	}
+	if revoked {
+		return respondWithInvalidToken(c, "The access token has been revoked")
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return respondWithInvalidToken(c, "The access token is invalid")
+	}
+
+	user, err := h.store.GetUserByID(c.Request().Context(), userID)
+	if err != nil || (user.Active.Valid && !user.Active.Bool) {
+		return respondWithInvalidToken(c, "The user of the access token no longer exists")
+	}
+
+	return c.JSON(int(utils.StatusCodeSuccess), buildUserInfo(user, claims.Scope))
+}
+
+// buildUserInfo maps a user to the standard claims released for the granted scopes
+func buildUserInfo(user sqlc.User, scope string) UserInfoResponse {
+	info := UserInfoResponse{
+		Subject: user.ID.String(),
+	}
+
+	if hasScope(scope, scopeProfile) {
+		info.Name = user.FullName
+		if user.DateOfBirth.Valid {
+			info.Birthdate = user.DateOfBirth.Time.Format("2006-01-02")
+		}
+		if user.UpdatedAt.Valid {
+			info.UpdatedAt = user.UpdatedAt.Time.Unix()
+		}
+	}
+
+	if hasScope(scope, scopeEmail) {
+		emailVerified := user.EmailVerified.Valid && user.EmailVerified.Bool
+		info.Email = user.Email
+		info.EmailVerified = &emailVerified
+	}
+
+	return info
+}
+
+// respondWithInvalidToken rejects a bearer token as described in RFC 6750 section 3
+func respondWithInvalidToken(c echo.Context, description string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+	return utils.RespondWithOAuthError(c, utils.StatusCodeUnauthorized, utils.OAuthErrorInvalidToken, description)
+}
@@ -0,0 +1,46 @@
+package scope
+
+import (
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// DeleteScope handles removing a scope from the registry. Clients can no longer request it,
+// tokens already issued with it are not affected.
+func (h *ScopeHandler) DeleteScope(c echo.Context) error {
+	name := c.Param("name")
+
+	// OpenID Connect cannot work without the openid scope
+	if name == "openid" {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeConflict,
+			"Scope in use",
+			utils.ErrorCodeResourceInUse,
+			"The openid scope is required by OpenID Connect and cannot be deleted",
+			nil,
+		)
+	}
+
+	deleted, err := h.store.DeleteScope(c.Request().Context(), name)
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to delete scope", err)
+	}
+	if deleted == 0 {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeNotFound,
+			"Scope not found",
+			utils.ErrorCodeResourceNotFound,
+			"The specified scope does not exist",
+			nil,
+		)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Scope deleted successfully",
+		nil,
+	)
+}
@@ -0,0 +1,26 @@
+package scope
+
+import (
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// ListScopes handles listing the registered scopes
+func (h *ScopeHandler) ListScopes(c echo.Context) error {
+	scopes, err := h.store.ListScopes(c.Request().Context())
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch scopes", err)
+	}
+
+	res := make([]ScopeResponse, 0, len(scopes))
+	for _, scope := range scopes {
+		res = append(res, toScopeResponse(scope))
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Scopes retrieved successfully",
+		res,
+	)
+}
@@ -0,0 +1,36 @@
+package scope
+
+import (
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+)
+
+// ==========
+// Scope DTOs
+// ==========
+
+// === Upsert Scope Dto ===
+type UpsertScopeRequest struct {
+	Description     string `json:"description" validate:"max=500"`
+	RequiresConsent bool   `json:"requires_consent"`
+}
+
+// === Scope Dto ===
+type ScopeResponse struct {
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	RequiresConsent bool      `json:"requires_consent"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func toScopeResponse(scope sqlc.Scope) ScopeResponse {
+	return ScopeResponse{
+		Name:            scope.Name,
+		Description:     scope.Description,
+		RequiresConsent: scope.RequiresConsent,
+		CreatedAt:       scope.CreatedAt.Time,
+		UpdatedAt:       scope.UpdatedAt.Time,
+	}
+}
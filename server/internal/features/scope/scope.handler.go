@@ -0,0 +1,20 @@
+package scope
+
+import (
+	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features"
+)
+
+type ScopeHandler struct {
+	store  *db.Store
+	config *config.Config
+}
+
+// NewScopeHandler creates a new scope registry handler
+func NewScopeHandler(ah *features.AppHandlers) *ScopeHandler {
+	return &ScopeHandler{
+		store:  ah.Store,
+		config: ah.Cfg,
+	}
+}
@@ -0,0 +1,58 @@
+package scope
+
+import (
+	"regexp"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// scopeNamePattern allows the scope token characters of RFC 6749 section 3.3
+var scopeNamePattern = regexp.MustCompile(`^[\x21\x23-\x5B\x5D-\x7E]{1,100}$`)
+
+// UpsertScope handles registering a scope or updating a registered one
+func (h *ScopeHandler) UpsertScope(c echo.Context) error {
+	name := c.Param("name")
+	if !scopeNamePattern.MatchString(name) {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid scope name",
+			utils.ErrorCodeInvalidRequest,
+			"Scope names must be 1 to 100 printable characters without spaces, quotes or backslashes",
+			nil,
+		)
+	}
+
+	req := new(UpsertScopeRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request format",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	scope, err := h.store.UpsertScope(c.Request().Context(), sqlc.UpsertScopeParams{
+		Name:            name,
+		Description:     req.Description,
+		RequiresConsent: req.RequiresConsent,
+	})
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to save scope", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Scope saved successfully",
+		toScopeResponse(scope),
+	)
+}
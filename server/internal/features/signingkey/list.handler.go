@@ -0,0 +1,54 @@
+package signingkey
+
+import (
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// ListSigningKeys handles listing every signing key with its lifecycle status
+func (h *SigningKeyHandler) ListSigningKeys(c echo.Context) error {
+	keys, err := h.store.ListSigningKeys(c.Request().Context())
+	if err != nil {
+		return utils.RespondWithInternalError(c, "Failed to fetch signing keys", err)
+	}
+
+	// The active key is picked among the keys that are still published
+	now := time.Now()
+	published := make([]sqlc.SigningKey, 0, len(keys))
+	for _, key := range keys {
+		if !key.RetiresAt.Valid || key.RetiresAt.Time.After(now) {
+			published = append(published, key)
+		}
+	}
+	active, _ := splitKeys(published, now)
+
+	response := make([]SigningKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, toSigningKeyResponse(key, keyStatus(key, active, now)))
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Signing keys retrieved successfully",
+		response,
+	)
+}
+
+// toSigningKeyResponse maps a stored key to its response, leaving out the private key
+func toSigningKeyResponse(key sqlc.SigningKey, status string) SigningKeyResponse {
+	response := SigningKeyResponse{
+		Kid:         key.Kid,
+		Algorithm:   key.Algorithm,
+		Status:      status,
+		ActivatesAt: key.ActivatesAt,
+		CreatedAt:   key.CreatedAt.Time,
+	}
+	if key.RetiresAt.Valid {
+		response.RetiresAt = &key.RetiresAt.Time
+	}
+	return response
+}
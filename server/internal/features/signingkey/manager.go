@@ -0,0 +1,321 @@
+package signingkey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+)
+
+// rotationLockID identifies the advisory lock that serializes rotation across server instances
+const rotationLockID int64 = 0x7369676e6b6579 // "signkey"
+
+// checkInterval is how often rotation is checked and the key set reloaded
+const checkInterval = time.Minute
+
+var (
+	ErrRotationInProgress = errors.New("another signing key rotation is in progress")
+	ErrKeyPending         = errors.New("a signing key is already waiting to activate")
+	ErrKeyActive          = errors.New("the active signing key cannot be retired")
+)
+
+// KeyManager keeps the signing keys stored in the database rotated and the in-memory key
+// set used to sign and verify tokens in sync with them
+type KeyManager struct {
+	store  *db.Store
+	config *config.Config
+}
+
+// NewKeyManager creates a new signing key manager
+func NewKeyManager(store *db.Store, cfg *config.Config) *KeyManager {
+	return &KeyManager{
+		store:  store,
+		config: cfg,
+	}
+}
+
+// Start brings the keys up to date, loads them and keeps rotating them in the background
+func (m *KeyManager) Start() error {
+	ctx := context.Background()
+	if err := m.Rotate(ctx); err != nil {
+		return err
+	}
+	if err := m.Reload(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := m.Rotate(ctx); err != nil {
+				log.Printf("Failed to rotate signing keys: %v", err)
+			}
+			if err := m.Reload(ctx); err != nil {
+				log.Printf("Failed to reload signing keys: %v", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Reload loads the published keys from the database into the in-memory key set
+func (m *KeyManager) Reload(ctx context.Context) error {
+	rows, err := m.store.ListPublishedSigningKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list signing keys: %w", err)
+	}
+
+	keys := make([]*utils.SigningKey, 0, len(rows))
+	for _, row := range rows {
+		key, err := m.decryptKey(row)
+		if err != nil {
+			return fmt.Errorf("failed to load signing key %s: %w", row.Kid, err)
+		}
+		keys = append(keys, key)
+	}
+
+	utils.SetSigningKeys(keys)
+	return nil
+}
+
+// Rotate makes sure a key is active, publishes the next key ahead of its activation and
+// schedules superseded keys for retirement once the tokens they signed have expired
+func (m *KeyManager) Rotate(ctx context.Context) error {
+	err := m.withLock(ctx, func(q *sqlc.Queries) error {
+		keys, err := q.ListPublishedSigningKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list signing keys: %w", err)
+		}
+
+		now := time.Now()
+		active, pending := splitKeys(keys, now)
+		if active == nil {
+			// Nothing can sign tokens, e.g. on first start, so the new key activates immediately
+			_, err := m.createKey(ctx, q, now)
+			return err
+		}
+
+		if err := m.retireSuperseded(ctx, q, keys, *active); err != nil {
+			return err
+		}
+
+		if pending != nil {
+			return nil
+		}
+		prePublish := m.config.JWT.KeyPrePublishAhead
+		activatesAt := active.ActivatesAt.Add(m.config.JWT.KeyRotationPeriod)
+		if now.Before(activatesAt.Add(-prePublish)) {
+			return nil
+		}
+		// Verifiers must be able to fetch the key before it signs anything
+		if earliest := now.Add(prePublish); activatesAt.Before(earliest) {
+			activatesAt = earliest
+		}
+		_, err = m.createKey(ctx, q, activatesAt)
+		return err
+	})
+	if errors.Is(err, ErrRotationInProgress) {
+		// Another instance is rotating, it will have done the work by the next check
+		return nil
+	}
+	return err
+}
+
+// RotateNow creates a new key on demand. It is published ahead of time like a scheduled
+// key unless immediate is set, in which case it signs tokens right away.
+func (m *KeyManager) RotateNow(ctx context.Context, immediate bool) (sqlc.SigningKey, error) {
+	var created sqlc.SigningKey
+	err := m.withLock(ctx, func(q *sqlc.Queries) error {
+		keys, err := q.ListPublishedSigningKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list signing keys: %w", err)
+		}
+
+		now := time.Now()
+		_, pending := splitKeys(keys, now)
+		if pending != nil && !immediate {
+			return ErrKeyPending
+		}
+
+		activatesAt := now.Add(m.config.JWT.KeyPrePublishAhead)
+		if immediate {
+			activatesAt = now
+		}
+		created, err = m.createKey(ctx, q, activatesAt)
+		if err != nil {
+			return err
+		}
+
+		if immediate {
+			return m.retireSuperseded(ctx, q, keys, created)
+		}
+		return nil
+	})
+	if err != nil {
+		return sqlc.SigningKey{}, err
+	}
+
+	return created, m.Reload(ctx)
+}
+
+// Retire stops publishing a key right away, invalidating every token it signed. The active
+// key cannot be retired, rotate immediately first.
+func (m *KeyManager) Retire(ctx context.Context, kid string) error {
+	err := m.withLock(ctx, func(q *sqlc.Queries) error {
+		key, err := q.GetSigningKeyByKid(ctx, kid)
+		if err != nil {
+			return err
+		}
+
+		keys, err := q.ListPublishedSigningKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list signing keys: %w", err)
+		}
+		if active, _ := splitKeys(keys, time.Now()); active != nil && active.ID == key.ID {
+			return ErrKeyActive
+		}
+
+		return q.SetSigningKeyRetiresAt(ctx, sqlc.SetSigningKeyRetiresAtParams{
+			ID:        key.ID,
+			RetiresAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.Reload(ctx)
+}
+
+// maxTokenLifetime is how long a token may still be presented after the key that signed
+// it stops signing, a superseded key stays published at least that long
+func (m *KeyManager) maxTokenLifetime() time.Duration {
+	return time.Duration(m.config.JWT.ExpiryHours) * time.Hour
+}
+
+// retireSuperseded schedules the retirement of keys that activated before active
+func (m *KeyManager) retireSuperseded(ctx context.Context, q *sqlc.Queries, keys []sqlc.SigningKey, active sqlc.SigningKey) error {
+	retiresAt := active.ActivatesAt.Add(m.maxTokenLifetime())
+	for _, key := range keys {
+		if key.RetiresAt.Valid || !key.ActivatesAt.Before(active.ActivatesAt) {
+			continue
+		}
+		err := q.SetSigningKeyRetiresAt(ctx, sqlc.SetSigningKeyRetiresAtParams{
+			ID:        key.ID,
+			RetiresAt: sql.NullTime{Time: retiresAt, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to schedule retirement of signing key %s: %w", key.Kid, err)
+		}
+	}
+	return nil
+}
+
+// createKey generates a key with the configured algorithm and stores it encrypted
+func (m *KeyManager) createKey(ctx context.Context, q *sqlc.Queries, activatesAt time.Time) (sqlc.SigningKey, error) {
+	key, err := utils.GenerateSigningKey(m.config.JWT.SigningAlgorithm)
+	if err != nil {
+		return sqlc.SigningKey{}, err
+	}
+
+	encoded, err := utils.EncodePrivateKey(key.PrivateKey)
+	if err != nil {
+		return sqlc.SigningKey{}, err
+	}
+	encrypted, err := utils.EncryptSecret(encoded, m.config.JWT.KeyEncryptionKey)
+	if err != nil {
+		return sqlc.SigningKey{}, fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+
+	created, err := q.CreateSigningKey(ctx, sqlc.CreateSigningKeyParams{
+		Kid:         key.KeyID,
+		Algorithm:   key.Algorithm,
+		PrivateKey:  encrypted,
+		ActivatesAt: activatesAt,
+	})
+	if err != nil {
+		return sqlc.SigningKey{}, fmt.Errorf("failed to store signing key: %w", err)
+	}
+
+	log.Printf("Created signing key %s, active from %s", created.Kid, activatesAt.Format(time.RFC3339))
+	return created, nil
+}
+
+// decryptKey turns a stored key back into a key that can sign and verify tokens
+func (m *KeyManager) decryptKey(row sqlc.SigningKey) (*utils.SigningKey, error) {
+	encoded, err := utils.DecryptSecret(row.PrivateKey, m.config.JWT.KeyEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := utils.DecodePrivateKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := utils.NewSigningKey(privateKey, row.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if key.KeyID != row.Kid {
+		return nil, errors.New("private key does not match its kid")
+	}
+
+	key.ActivatesAt = row.ActivatesAt
+	return key, nil
+}
+
+// withLock runs fn in a transaction holding the rotation lock, so that concurrent
+// instances never create keys at the same time
+func (m *KeyManager) withLock(ctx context.Context, fn func(*sqlc.Queries) error) error {
+	return m.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		locked, err := q.TryAdvisoryXactLock(ctx, rotationLockID)
+		if err != nil {
+			return fmt.Errorf("failed to acquire signing key lock: %w", err)
+		}
+		if !locked {
+			return ErrRotationInProgress
+		}
+		return fn(q)
+	})
+}
+
+// splitKeys returns the key currently signing tokens and the next key waiting to activate
+func splitKeys(keys []sqlc.SigningKey, now time.Time) (active, pending *sqlc.SigningKey) {
+	for i := range keys {
+		key := &keys[i]
+		if key.ActivatesAt.After(now) {
+			if pending == nil || key.ActivatesAt.Before(pending.ActivatesAt) {
+				pending = key
+			}
+			continue
+		}
+		if active == nil || key.ActivatesAt.After(active.ActivatesAt) {
+			active = key
+		}
+	}
+	return active, pending
+}
+
+// keyStatus describes where a key is in its lifecycle
+func keyStatus(key sqlc.SigningKey, active *sqlc.SigningKey, now time.Time) string {
+	switch {
+	case key.RetiresAt.Valid && !key.RetiresAt.Time.After(now):
+		return statusRetired
+	case key.ActivatesAt.After(now):
+		return statusPending
+	case active != nil && active.ID == key.ID:
+		return statusActive
+	default:
+		return statusRetiring
+	}
+}
@@ -0,0 +1,56 @@
+package signingkey
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// RetireSigningKey handles withdrawing a signing key, e.g. after it has been compromised.
+// Tokens signed with it stop validating immediately.
+func (h *SigningKeyHandler) RetireSigningKey(c echo.Context) error {
+	kid := c.Param("kid")
+
+	err := h.manager.Retire(c.Request().Context(), kid)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeNotFound,
+				"Signing key not found",
+				utils.ErrorCodeResourceNotFound,
+				"The specified signing key does not exist",
+				nil,
+			)
+		case errors.Is(err, ErrKeyActive):
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeConflict,
+				"Signing key is active",
+				utils.ErrorCodeResourceInUse,
+				"The active signing key cannot be retired, rotate immediately first",
+				nil,
+			)
+		case errors.Is(err, ErrRotationInProgress):
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeConflict,
+				"Rotation in progress",
+				utils.ErrorCodeResourceInUse,
+				"Another signing key rotation is in progress, try again shortly",
+				nil,
+			)
+		}
+		return utils.RespondWithInternalError(c, "Failed to retire signing key", err)
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeSuccess,
+		"Signing key retired successfully",
+		nil,
+	)
+}
@@ -0,0 +1,61 @@
+package signingkey
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// RotateSigningKey handles creating a new signing key ahead of the rotation schedule
+func (h *SigningKeyHandler) RotateSigningKey(c echo.Context) error {
+	var req RotateSigningKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.RespondWithError(
+			c,
+			utils.StatusCodeBadRequest,
+			"Invalid request format",
+			utils.ErrorCodeInvalidRequest,
+			"Could not parse request body",
+			err,
+		)
+	}
+
+	key, err := h.manager.RotateNow(c.Request().Context(), req.Immediate)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrKeyPending):
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeConflict,
+				"Rotation already scheduled",
+				utils.ErrorCodeResourceInUse,
+				"A signing key is already waiting to activate, rotate immediately to replace the active key now",
+				nil,
+			)
+		case errors.Is(err, ErrRotationInProgress):
+			return utils.RespondWithError(
+				c,
+				utils.StatusCodeConflict,
+				"Rotation in progress",
+				utils.ErrorCodeResourceInUse,
+				"Another signing key rotation is in progress, try again shortly",
+				nil,
+			)
+		}
+		return utils.RespondWithInternalError(c, "Failed to rotate signing key", err)
+	}
+
+	status := statusPending
+	if !key.ActivatesAt.After(time.Now()) {
+		status = statusActive
+	}
+
+	return utils.RespondWithSuccess(
+		c,
+		utils.StatusCodeCreated,
+		"Signing key created successfully",
+		toSigningKeyResponse(key, status),
+	)
+}
@@ -0,0 +1,32 @@
+package signingkey
+
+import "time"
+
+// ==========
+// Signing Key DTOs
+// ==========
+
+// Lifecycle states of a signing key
+const (
+	statusPending  = "pending"  // Published in the JWKS, not signing yet
+	statusActive   = "active"   // Signing new tokens
+	statusRetiring = "retiring" // Superseded, still published until tokens it signed expire
+	statusRetired  = "retired"  // No longer published or accepted
+)
+
+// === Signing Key Dto ===
+type SigningKeyResponse struct {
+	Kid         string     `json:"kid"`
+	Algorithm   string     `json:"algorithm"`
+	Status      string     `json:"status"`
+	ActivatesAt time.Time  `json:"activates_at"`
+	RetiresAt   *time.Time `json:"retires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// === Rotate Signing Key Dto ===
+type RotateSigningKeyRequest struct {
+	// Immediate activates the new key right away instead of publishing it ahead of time,
+	// for when the active key is compromised
+	Immediate bool `json:"immediate"`
+}
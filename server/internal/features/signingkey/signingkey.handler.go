@@ -0,0 +1,22 @@
+package signingkey
+
+import (
+	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features"
+)
+
+type SigningKeyHandler struct {
+	store   *db.Store
+	config  *config.Config
+	manager *KeyManager
+}
+
+// NewSigningKeyHandler creates a new signing key administration handler
+func NewSigningKeyHandler(ah *features.AppHandlers) *SigningKeyHandler {
+	return &SigningKeyHandler{
+		store:   ah.Store,
+		config:  ah.Cfg,
+		manager: NewKeyManager(ah.Store, ah.Cfg),
+	}
+}
@@ -0,0 +1,67 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Mail drivers, selecting how emails are delivered
+const (
+	DriverSMTP   = "smtp"   // Sent through an SMTP server
+	DriverOutbox = "outbox" // Written to files in a directory, for local development and tests
+	DriverLog    = "log"    // Written to the log
+)
+
+// Config contains the mail delivery settings
+type Config struct {
+	Driver    string // One of the mail drivers
+	From      string // Address emails are sent from
+	FromName  string // Display name emails are sent from
+	Host      string // SMTP server host
+	Port      int    // SMTP server port, 465 uses implicit TLS and other ports STARTTLS when offered
+	Username  string // SMTP username, authentication is skipped when empty
+	Password  string // SMTP password
+	OutboxDir string // Directory the outbox driver writes emails to
+}
+
+// Message is an email to a single recipient with a plain text body and an optional HTML body
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends the emails of the auth flows
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New creates the mailer of the configured driver
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(cfg)
+	case DriverOutbox:
+		return NewOutboxMailer(cfg)
+	case DriverLog:
+		return NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
+
+// LogMailer writes emails to the log instead of sending them
+type LogMailer struct{}
+
+// NewLogMailer creates a mailer that only logs the emails it is given
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs the recipient, subject and plain text body of the email
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("MAIL to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// OutboxMailer writes every email as an .eml file to a directory instead of sending it, so
+// that emails can be opened in a mail client during local development and read by tests
+type OutboxMailer struct {
+	dir  string
+	from string
+}
+
+// NewOutboxMailer creates a mailer writing to the configured outbox directory, which is
+// created when missing
+func NewOutboxMailer(cfg Config) (*OutboxMailer, error) {
+	if cfg.OutboxDir == "" {
+		return nil, errors.New("outbox mail driver requires a directory")
+	}
+	if err := os.MkdirAll(cfg.OutboxDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	return &OutboxMailer{
+		dir:  cfg.OutboxDir,
+		from: formatAddress(cfg.From, cfg.FromName),
+	}, nil
+}
+
+// Send writes the email to a new file named after the time it was sent
+func (m *OutboxMailer) Send(ctx context.Context, msg Message) error {
+	body, err := buildMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	suffix, err := randomHex(4)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), suffix)
+	path := filepath.Join(m.dir, name)
+
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return fmt.Errorf("failed to write email to outbox: %w", err)
+	}
+
+	log.Printf("MAIL to=%s subject=%q written to %s", msg.To, msg.Subject, path)
+	return nil
+}
@@ -0,0 +1,113 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Email templates. Each has a text and an HTML version, the text version also defines the subject.
+const (
+	TemplateEmailVerification = "email_verification" // Data is LinkData
+	TemplatePasswordReset     = "password_reset"     // Data is LinkData
+	TemplatePasswordChanged   = "password_changed"   // Data is PasswordChangedData
+	TemplateNewDeviceLogin    = "new_device_login"   // Data is NewDeviceLoginData
+	TemplateInvitation        = "invitation"         // Data is InvitationData
+	TemplateAccountLocked     = "account_locked"     // Data is LinkData
+)
+
+// LinkData is the data of emails asking the recipient to open a link before it expires
+type LinkData struct {
+	Name      string
+	Link      string
+	ExpiresIn time.Duration
+}
+
+// PasswordChangedData is the data of emails telling a user their password was changed
+type PasswordChangedData struct {
+	Name          string
+	Time          time.Time
+	IPAddress     string
+	SessionsEnded bool // Whether the sessions on other devices were logged out
+}
+
+// NewDeviceLoginData is the data of emails telling a user about a login from a new device
+type NewDeviceLoginData struct {
+	Name      string
+	Time      time.Time
+	IPAddress string
+	UserAgent string
+	Link      string // Where the user can review their sessions
+}
+
+// InvitationData is the data of emails inviting someone to create an account
+type InvitationData struct {
+	InviterName string
+	Link        string
+	ExpiresIn   time.Duration
+}
+
+var templateFuncs = map[string]any{
+	"duration": formatDuration,
+	"datetime": func(t time.Time) string { return t.UTC().Format("January 2, 2006 at 15:04 UTC") },
+}
+
+// NewTemplateMessage renders an email template for a recipient
+func NewTemplateMessage(to, name string, data any) (Message, error) {
+	textTmpl, err := texttemplate.New(name+".txt").Funcs(templateFuncs).
+		ParseFS(templateFS, "templates/"+name+".txt")
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	htmlTmpl, err := htmltemplate.New("layout.html").Funcs(templateFuncs).
+		ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+// formatDuration describes an expiry in words, e.g. "24 hours" or "15 minutes"
+func formatDuration(d time.Duration) string {
+	unit := func(n int, name string) string {
+		if n == 1 {
+			return "1 " + name
+		}
+		return fmt.Sprintf("%d %ss", n, name)
+	}
+
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return unit(int(d/(24*time.Hour)), "day")
+	case d >= time.Hour && d%time.Hour == 0:
+		return unit(int(d/time.Hour), "hour")
+	case d >= time.Minute:
+		return unit(int(d/time.Minute), "minute")
+	default:
+		return unit(int(d/time.Second), "second")
+	}
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"slices"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// AdminMiddleware only lets users with the admin role through. It must run after AuthMiddleware.
+func (m *Middleware) AdminMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := c.Get("user_claims").(*utils.AccessTokenClaims)
+			if !ok || !claims.HasRole(utils.RoleAdmin) {
+				return utils.RespondWithError(
+					c,
+					utils.StatusCodeForbidden,
+					"Forbidden",
+					utils.ErrorCodeForbidden,
+					"Admin access is required",
+					nil,
+				)
+			}
+
+			// Admins must have logged in with a second factor, a TOTP code or a security key
+			if m.Config.Auth.AdminMFARequired && !slices.Contains(claims.AMR, "otp") && !slices.Contains(claims.AMR, "hwk") {
+				return utils.RespondWithError(
+					c,
+					utils.StatusCodeForbidden,
+					"Forbidden",
+					utils.ErrorCodeMFARequired,
+					"Admin access requires logging in with multi-factor authentication",
+					nil,
+				)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -57,6 +57,47 @@ func (m *Middleware) AuthMiddleware() echo.MiddlewareFunc {
 				)
 			}
 
+			// Revoked tokens are denied until they expire
+			revoked, err := m.Store.IsAccessTokenRevoked(c.Request().Context(), claims.ID)
+			if err != nil {
+				return utils.RespondWithInternalError(c, "Failed to check token revocation", err)
+			}
+			if revoked {
+				return utils.RespondWithError(
+					c,
+					utils.StatusCodeUnauthorized,
+					"Unauthorized",
+					utils.ErrorCodeUnauthorized,
+					"Token has been revoked",
+					nil,
+				)
+			}
+
+			// Client credentials tokens carry no user and cannot access user endpoints
+			if claims.IsClientToken() {
+				return utils.RespondWithError(
+					c,
+					utils.StatusCodeForbidden,
+					"Forbidden",
+					utils.ErrorCodeForbidden,
+					"Client tokens cannot access user endpoints",
+					nil,
+				)
+			}
+
+			// Tokens issued to OAuth clients are limited to their scopes at the OAuth endpoints,
+			// only first-party login sessions can access user endpoints
+			if claims.IsOAuthToken() {
+				return utils.RespondWithError(
+					c,
+					utils.StatusCodeForbidden,
+					"Forbidden",
+					utils.ErrorCodeForbidden,
+					"Tokens issued to OAuth clients cannot access user endpoints",
+					nil,
+				)
+			}
+
 			// Store user information in context
 			c.Set("user_id", claims.UserID)
 			c.Set("user_email", claims.Email)
@@ -94,8 +135,13 @@ func (m *Middleware) OptionalAuthMiddleware() echo.MiddlewareFunc {
 
 			// Validate the token
 			claims, err := utils.ValidateToken(token)
-			if err != nil {
-				// Invalid token, continue without authentication
+			if err != nil || claims.IsClientToken() || claims.IsOAuthToken() {
+				// Invalid or non-first-party token, continue without authentication
+				return next(c)
+			}
+
+			// Revoked tokens are treated as missing
+			if revoked, err := m.Store.IsAccessTokenRevoked(c.Request().Context(), claims.ID); err != nil || revoked {
 				return next(c)
 			}
 
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/ratelimit"
 	"github.com/labstack/echo/v4"
 )
 
@@ -12,17 +13,22 @@ type IMiddleware interface {
 	ValidationMiddleware() echo.MiddlewareFunc
 	AuthMiddleware() echo.MiddlewareFunc
 	OptionalAuthMiddleware() echo.MiddlewareFunc
+	AdminMiddleware() echo.MiddlewareFunc
+	RequirePermission(permissions ...string) echo.MiddlewareFunc
+	RateLimitMiddleware(name string, limit ratelimit.Limit) echo.MiddlewareFunc
 }
 
 type Middleware struct {
-	Store  *db.Store
-	Config *config.Config
+	Store   *db.Store
+	Config  *config.Config
+	Limiter ratelimit.Limiter // Backend of RateLimitMiddleware, rate limits are skipped when nil
 }
 
 func NewMiddleware(m Middleware) IMiddleware {
 	return &Middleware{
-		Store:  m.Store,
-		Config: m.Config,
+		Store:   m.Store,
+		Config:  m.Config,
+		Limiter: m.Limiter,
 	}
 }
 
@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"fmt"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// RequirePermission only lets users through whose roles grant every one of the permissions. It
+// must run after AuthMiddleware. Permissions are only carried by the tokens of login sessions,
+// tokens issued to OAuth clients never pass.
+func (m *Middleware) RequirePermission(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := c.Get("user_claims").(*utils.AccessTokenClaims)
+			if !ok {
+				return utils.RespondWithError(
+					c,
+					utils.StatusCodeForbidden,
+					"Forbidden",
+					utils.ErrorCodeForbidden,
+					"Authentication is required",
+					nil,
+				)
+			}
+
+			for _, permission := range permissions {
+				if !claims.HasPermission(permission) {
+					return utils.RespondWithError(
+						c,
+						utils.StatusCodeForbidden,
+						"Forbidden",
+						utils.ErrorCodeForbidden,
+						fmt.Sprintf("The %s permission is required", permission),
+						nil,
+					)
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
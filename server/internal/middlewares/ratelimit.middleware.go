@@ -0,0 +1,120 @@
+package middlewares
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/ratelimit"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitMiddleware limits how often the routes it is applied to can be called. name separates
+// the buckets of different limits, routes sharing a name share their buckets. The state of the
+// bucket is reported in the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers.
+// Requests are let through when the limiter fails, an outage must not take down logins.
+func (m *Middleware) RateLimitMiddleware(name string, limit ratelimit.Limit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if m.Limiter == nil || !m.Config.RateLimit.Enabled || !limit.Enabled() {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			key := name + ":" + rateLimitSubject(c, limit.Key)
+			res, err := m.Limiter.Take(c.Request().Context(), key, limit)
+			if err != nil {
+				log.Printf("Failed to apply rate limit %q: %v", name, err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("RateLimit-Limit", fmt.Sprint(res.Limit))
+			header.Set("RateLimit-Remaining", fmt.Sprint(res.Remaining))
+			header.Set("RateLimit-Reset", fmt.Sprint(ceilSeconds(res.Reset)))
+
+			if !res.Allowed {
+				retryAfter := ceilSeconds(res.RetryAfter)
+				header.Set("Retry-After", fmt.Sprint(retryAfter))
+				return utils.RespondWithError(
+					c,
+					utils.StatusCodeTooManyRequests,
+					"Too many requests",
+					utils.ErrorCodeRateLimitExceeded,
+					"Too many requests, please try again later",
+					map[string]any{
+						"retry_after": retryAfter,
+					},
+				)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// rateLimitSubject tells who the bucket of a request belongs to. Requests that do not identify
+// the user or client the limit is kept for fall back to their IP address. The client is not
+// authenticated yet, so its buckets are kept per IP address too: others cannot use up the
+// bucket of a client, and made up client IDs only get buckets of clients that do not exist.
+func rateLimitSubject(c echo.Context, key string) string {
+	switch key {
+	case ratelimit.KeyUser:
+		if claims := requestClaims(c); claims != nil {
+			if claims.IsClientToken() {
+				return "client:" + claims.ClientID
+			}
+			return "user:" + claims.UserID
+		}
+	case ratelimit.KeyClient:
+		if clientID := requestClientID(c); clientID != "" {
+			return "client:" + clientID + ":ip:" + c.RealIP()
+		}
+	}
+	return "ip:" + c.RealIP()
+}
+
+// requestClaims returns the claims of the access token of a request. Rate limits run before
+// AuthMiddleware, so the token is validated here when it has not been yet. Revocation is not
+// checked, a revoked token only counts against its own user.
+func requestClaims(c echo.Context) *utils.AccessTokenClaims {
+	if claims, ok := c.Get("user_claims").(*utils.AccessTokenClaims); ok {
+		return claims
+	}
+
+	token := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		cookie, err := c.Cookie("access_token")
+		if err != nil {
+			return nil
+		}
+		token = cookie.Value
+	}
+
+	claims, err := utils.ValidateToken(token)
+	if err != nil {
+		return nil
+	}
+	return claims
+}
+
+// requestClientID returns the client a request to an OAuth endpoint identifies itself as, from
+// HTTP Basic authentication or the client_id parameter. The client is not authenticated here.
+func requestClientID(c echo.Context) string {
+	if username, _, ok := c.Request().BasicAuth(); ok {
+		// Client credentials are form encoded before they are put in the header (RFC 6749 2.3.1)
+		if clientID, err := url.QueryUnescape(username); err == nil {
+			return clientID
+		}
+		return username
+	}
+	return c.FormValue("client_id")
+}
+
+// ceilSeconds rounds a duration up to whole seconds for the rate limit headers
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
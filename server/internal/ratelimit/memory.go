@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cleanupInterval is how often buckets that are full again are forgotten
+const cleanupInterval = time.Minute
+
+// memoryBucket is a bucket kept in memory, it can be forgotten after expiresAt
+type memoryBucket struct {
+	bucket
+	expiresAt time.Time
+}
+
+// MemoryLimiter keeps the buckets in the memory of the server. Every server instance limits
+// requests on its own.
+type MemoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+// NewMemoryLimiter creates an in-memory limiter and starts forgetting buckets that are full again
+func NewMemoryLimiter() *MemoryLimiter {
+	l := &MemoryLimiter{
+		buckets: make(map[string]*memoryBucket),
+	}
+
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			l.cleanup(time.Now())
+		}
+	}()
+
+	return l
+}
+
+// Take takes a token from the bucket of key
+func (l *MemoryLimiter) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: bucket{tokens: limit.capacity(), updatedAt: now}}
+		l.buckets[key] = b
+	}
+
+	var res Result
+	b.bucket, res = b.take(limit, now)
+	b.expiresAt = now.Add(res.Reset)
+	return res, nil
+}
+
+// cleanup forgets the buckets that are full again, they are the same as new buckets
+func (l *MemoryLimiter) cleanup(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, b := range l.buckets {
+		if now.After(b.expiresAt) {
+			delete(l.buckets, key)
+		}
+	}
+}
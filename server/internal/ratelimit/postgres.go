@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db/sqlc"
+)
+
+// PostgresLimiter keeps the buckets in the database, so that all server instances share them.
+// Buckets are refilled with the database clock, the clocks of the instances do not matter.
+type PostgresLimiter struct {
+	store *db.Store
+}
+
+// NewPostgresLimiter creates a database backed limiter and starts deleting buckets that are
+// full again
+func NewPostgresLimiter(store *db.Store) *PostgresLimiter {
+	l := &PostgresLimiter{store: store}
+
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := store.DeleteExpiredRateLimitBuckets(context.Background()); err != nil {
+				log.Printf("Failed to delete expired rate limit buckets: %v", err)
+			}
+		}
+	}()
+
+	return l
+}
+
+// Take takes a token from the bucket of key. The bucket is locked while it is updated, so that
+// concurrent requests on any instance take their tokens one after another.
+func (l *PostgresLimiter) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	var res Result
+	err := l.store.ExecTx(ctx, func(q *sqlc.Queries) error {
+		err := q.CreateRateLimitBucket(ctx, sqlc.CreateRateLimitBucketParams{
+			Key:    key,
+			Tokens: limit.capacity(),
+		})
+		if err != nil {
+			return err
+		}
+
+		row, err := q.GetRateLimitBucketForUpdate(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		var b bucket
+		b, res = bucket{tokens: row.Tokens, updatedAt: row.UpdatedAt}.take(limit, row.Now)
+
+		return q.UpdateRateLimitBucket(ctx, sqlc.UpdateRateLimitBucketParams{
+			Key:       key,
+			Tokens:    b.tokens,
+			UpdatedAt: b.updatedAt,
+			ExpiresAt: b.updatedAt.Add(res.Reset),
+		})
+	})
+	return res, err
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+)
+
+// Rate limit backends, selecting where the buckets are kept
+const (
+	BackendMemory   = "memory"   // In the memory of the server, for single instance deployments
+	BackendPostgres = "postgres" // In the database, shared by all server instances
+)
+
+// Rate limit keys, selecting who a bucket is kept for
+const (
+	KeyIP     = "ip"     // The IP address of the request
+	KeyUser   = "user"   // The user of the access token, or the IP address without one
+	KeyClient = "client" // The client the request identifies from its IP address, or the IP address without one
+)
+
+// Config contains the rate limits of the route groups
+type Config struct {
+	Enabled  bool   // Whether requests are rate limited at all
+	Backend  string // One of the rate limit backends
+	API      Limit  // Limit of all API routes
+	Login    Limit  // Additional limit of the login routes
+	Register Limit  // Additional limit of user registration
+	Token    Limit  // Additional limit of the OAuth token endpoint
+
+	// Additional limit of entering device user codes (RFC 8628 section 5.1)
+	DeviceVerification Limit
+}
+
+// Limit is a token bucket. Every request takes a token from the bucket, which is refilled with
+// Requests tokens every Period and holds up to Burst tokens.
+type Limit struct {
+	Requests int           // Tokens added every period, zero disables the limit
+	Period   time.Duration // Time it takes to add Requests tokens
+	Burst    int           // Most tokens the bucket holds, Requests when zero
+	Key      string        // One of the rate limit keys
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// capacity is the number of tokens a full bucket holds
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate is the number of tokens added per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result describes the bucket of a request after taking a token from it
+type Result struct {
+	Allowed    bool          // Whether a token was available
+	Limit      int           // Tokens a full bucket holds
+	Remaining  int           // Whole tokens left in the bucket
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next token is available, zero when allowed
+}
+
+// Limiter takes tokens from the buckets of a backend
+type Limiter interface {
+	// Take takes a token from the bucket of key, which is created full when it does not exist
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// New creates the limiter of the configured backend. The Postgres backend keeps its buckets in
+// the database of store.
+func New(cfg Config, store *db.Store) (Limiter, error) {
+	switch cfg.Backend {
+	case BackendMemory:
+		return NewMemoryLimiter(), nil
+	case BackendPostgres:
+		if store == nil {
+			return nil, errors.New("the postgres rate limit backend requires a database connection")
+		}
+		return NewPostgresLimiter(store), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", cfg.Backend)
+	}
+}
+
+// bucket is the state of a token bucket, refilled up to updatedAt
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// take refills a bucket up to now and takes a token from it when one is available
+func (b bucket) take(limit Limit, now time.Time) (bucket, Result) {
+	rate := limit.rate()
+	capacity := limit.capacity()
+
+	elapsed := max(now.Sub(b.updatedAt).Seconds(), 0)
+	tokens := min(b.tokens+elapsed*rate, capacity)
+
+	res := Result{Limit: int(capacity)}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = seconds((capacity - tokens) / rate)
+
+	return bucket{tokens: tokens, updatedAt: now}, res
+}
+
+// seconds converts a number of seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
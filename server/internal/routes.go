@@ -1,50 +1,147 @@
 package internal
 
 import (
+	"log"
+
 	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/features"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/features/auth"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/features/client"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/features/health"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features/oauth"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features/scope"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features/signingkey"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/mailer"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/middlewares"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
 	"github.com/labstack/echo/v4"
 )
 
 // setupRoutes configures all routes for the application
 func SetupRoutes(e *echo.Echo, store *db.Store, cfg *config.Config, cm middlewares.IMiddleware) {
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		log.Fatalf("Failed to set up mail delivery: %v", err)
+	}
+
 	ah := &features.AppHandlers{
-		Store: store,
-		Cfg:   cfg,
+		Store:  store,
+		Cfg:    cfg,
+		Mailer: mail,
 	}
 
 	healthHandler := health.NewHealthHandler(ah)
 	authHandler := auth.NewAuthHandler(ah)
 	clientHandler := client.NewClientHandler(ah)
+	oauthHandler := oauth.NewOAuthHandler(ah)
+	signingKeyHandler := signingkey.NewSigningKeyHandler(ah)
+	scopeHandler := scope.NewScopeHandler(ah)
+
+	// Rate limits - Every API route counts against the API limit, the login, registration, token
+	// and device verification endpoints have stricter limits on top of it
+	apiLimit := cm.RateLimitMiddleware("api", cfg.RateLimit.API)
+	loginLimit := cm.RateLimitMiddleware("login", cfg.RateLimit.Login)
+	registerLimit := cm.RateLimitMiddleware("register", cfg.RateLimit.Register)
+	tokenLimit := cm.RateLimitMiddleware("token", cfg.RateLimit.Token)
+	deviceVerificationLimit := cm.RateLimitMiddleware("device_verification", cfg.RateLimit.DeviceVerification)
 
 	// API v1 group - Register API routes FIRST
-	v1 := e.Group("/api/v1")
+	v1 := e.Group("/api/v1", apiLimit)
 
 	// Health check - public
 	v1.GET("/health", healthHandler.Check)
 
 	// Auth Endpoints - Public
-	v1.POST("/auth/register", authHandler.Register)    // User registration
-	v1.POST("/auth/login", authHandler.Login)          // User login
-	v1.POST("/auth/logout", authHandler.Logout)        // User logout
-	v1.POST("/auth/refresh", authHandler.RefreshToken) // Refresh access token
+	v1.POST("/auth/register", authHandler.Register, registerLimit)                           // User registration
+	v1.POST("/auth/login", authHandler.Login, loginLimit)                                    // User login
+	v1.POST("/auth/login/mfa", authHandler.LoginMFA, loginLimit)                             // Complete a login with a second factor
+	v1.POST("/auth/login/mfa/webauthn/begin", authHandler.BeginLoginMFAWebAuthn, loginLimit) // Start completing a login with a security key
+	v1.POST("/auth/login/mfa/webauthn", authHandler.LoginMFAWebAuthn, loginLimit)            // Complete a login with a security key
+	v1.POST("/auth/login/passkey/begin", authHandler.BeginPasskeyLogin, loginLimit)          // Start a passwordless login
+	v1.POST("/auth/login/passkey", authHandler.PasskeyLogin, loginLimit)                     // Log in with a passkey
+	v1.POST("/auth/logout", authHandler.Logout)                                              // User logout
+	v1.POST("/auth/refresh", authHandler.RefreshToken)                                       // Refresh access token
+	v1.POST("/auth/verify-email", authHandler.VerifyEmail)                                   // Verify an email address
+	v1.POST("/auth/verify-email/resend", authHandler.ResendVerificationEmail)                // Resend the verification email
+	v1.POST("/auth/forgot-password", authHandler.ForgotPassword)                             // Request a password reset email
+	v1.POST("/auth/reset-password", authHandler.ResetPassword)                               // Set a new password with a reset link
+	v1.POST("/auth/unlock-account", authHandler.UnlockAccount)                               // Lift a login lockout with an unlock link
 
 	// Auth Endpoints - Authenticated
 	v1.POST("/auth/logout-all", authHandler.LogoutAll, cm.AuthMiddleware()) // User logout from all devices
 
-	// Client Endpoints - TODO: Add authentication middleware when available
-	v1.POST("/clients", clientHandler.CreateClient)                                                               // Create new client
-	v1.GET("/clients", clientHandler.GetAllClients)                                                               // List all clients
-	v1.GET("/clients/:id", clientHandler.GetClientById)                                                           // Get client by UUID
-	v1.PUT("/clients/:id", clientHandler.UpdateClient)                                                            // Update client by UUID
-	v1.DELETE("/clients/:id", clientHandler.DeleteClient)                                                         // Delete client by UUID
-	v1.POST("/clients/:id/regenerate-secret", clientHandler.RegenerateClientSecret)                               // Regenerate secret by UUID
-	v1.POST("/clients/by-client-id/:client_id/regenerate-secret", clientHandler.RegenerateClientSecretByClientID) // Regenerate secret by client_id
+	// Client Endpoints - Authenticated, managing clients requires the clients permissions
+	clientsRead := cm.RequirePermission(utils.PermissionClientsRead)
+	clientsWrite := cm.RequirePermission(utils.PermissionClientsWrite)
+	v1.POST("/clients", clientHandler.CreateClient, cm.AuthMiddleware(), clientsWrite)                                                               // Create new client
+	v1.GET("/clients", clientHandler.GetAllClients, cm.AuthMiddleware(), clientsRead)                                                                // List all clients
+	v1.GET("/clients/:id", clientHandler.GetClientById, cm.AuthMiddleware(), clientsRead)                                                            // Get client by UUID
+	v1.PUT("/clients/:id", clientHandler.UpdateClient, cm.AuthMiddleware(), clientsWrite)                                                            // Update client by UUID
+	v1.DELETE("/clients/:id", clientHandler.DeleteClient, cm.AuthMiddleware(), clientsWrite)                                                         // Delete client by UUID
+	v1.POST("/clients/:id/regenerate-secret", clientHandler.RegenerateClientSecret, cm.AuthMiddleware(), clientsWrite)                               // Regenerate secret by UUID
+	v1.POST("/clients/by-client-id/:client_id/regenerate-secret", clientHandler.RegenerateClientSecretByClientID, cm.AuthMiddleware(), clientsWrite) // Regenerate secret by client_id
+
+	// OAuth consent - The consent page acts on behalf of the logged-in session
+	v1.GET("/oauth/consent", oauthHandler.GetConsent)     // Describe a pending authorization request
+	v1.POST("/oauth/consent", oauthHandler.SubmitConsent) // Approve or deny a pending authorization request
+
+	// OAuth device verification - The verification page acts on behalf of the logged-in session
+	v1.GET("/oauth/device", oauthHandler.GetDeviceVerification, deviceVerificationLimit)     // Describe the request behind a user code
+	v1.POST("/oauth/device", oauthHandler.SubmitDeviceVerification, deviceVerificationLimit) // Approve or deny the request behind a user code
+
+	// Account Endpoints - Authenticated
+	v1.GET("/me", authHandler.GetProfile, cm.AuthMiddleware())               // Get the user's profile
+	v1.PATCH("/me", authHandler.UpdateProfile, cm.AuthMiddleware())          // Update the user's profile
+	v1.POST("/me/password", authHandler.ChangePassword, cm.AuthMiddleware()) // Change the user's password
+	v1.POST("/me/email", authHandler.ChangeEmail, cm.AuthMiddleware())       // Change the user's email, pending verification
+
+	// Multi-factor authentication - Authenticated
+	v1.GET("/me/mfa", authHandler.GetMFAStatus, cm.AuthMiddleware())                            // Get the user's MFA status
+	v1.POST("/me/mfa/totp", authHandler.EnrollTOTP, cm.AuthMiddleware())                        // Start setting up a TOTP authenticator
+	v1.POST("/me/mfa/totp/confirm", authHandler.ConfirmTOTP, cm.AuthMiddleware())               // Enable MFA with a first TOTP code
+	v1.POST("/me/mfa/recovery-codes", authHandler.RegenerateRecoveryCodes, cm.AuthMiddleware()) // Replace the recovery codes
+	v1.POST("/me/mfa/disable", authHandler.DisableMFA, cm.AuthMiddleware())                     // Disable MFA
+
+	// Security keys and passkeys - Authenticated
+	v1.GET("/me/webauthn", authHandler.ListWebAuthnCredentials, cm.AuthMiddleware())                     // List the user's security keys
+	v1.POST("/me/webauthn/register", authHandler.BeginWebAuthnRegistration, cm.AuthMiddleware())         // Start registering a security key
+	v1.POST("/me/webauthn/register/finish", authHandler.FinishWebAuthnRegistration, cm.AuthMiddleware()) // Store a registered security key
+	v1.DELETE("/me/webauthn/:id", authHandler.DeleteWebAuthnCredential, cm.AuthMiddleware())             // Remove a security key
+
+	// Authorized apps - Authenticated
+	v1.GET("/me/authorized-apps", oauthHandler.ListAuthorizedApps, cm.AuthMiddleware())                // List apps the user has authorized
+	v1.DELETE("/me/authorized-apps/:client_id", oauthHandler.RevokeAuthorizedApp, cm.AuthMiddleware()) // Revoke an app's access
+
+	// Admin Endpoints - Admin role only
+	admin := v1.Group("/admin", cm.AuthMiddleware(), cm.AdminMiddleware())
+	admin.GET("/signing-keys", signingKeyHandler.ListSigningKeys)          // List signing keys
+	admin.POST("/signing-keys/rotate", signingKeyHandler.RotateSigningKey) // Create the next signing key
+	admin.DELETE("/signing-keys/:kid", signingKeyHandler.RetireSigningKey) // Retire a signing key
+	admin.GET("/scopes", scopeHandler.ListScopes)                          // List registered scopes
+	admin.PUT("/scopes/:name", scopeHandler.UpsertScope)                   // Register or update a scope
+	admin.DELETE("/scopes/:name", scopeHandler.DeleteScope)                // Remove a scope
+	admin.POST("/users/:id/unlock", authHandler.AdminUnlockUser)           // Lift a login lockout
+
+	// OAuth 2.0 Endpoints - Public, the authorization endpoint checks the session cookie itself
+	oauthGroup := e.Group("/oauth", apiLimit)
+	oauthGroup.GET("/authorize", oauthHandler.Authorize)                        // Authorization endpoint
+	oauthGroup.POST("/token", oauthHandler.Token, tokenLimit)                   // Token endpoint
+	oauthGroup.POST("/device_authorization", oauthHandler.DeviceAuthorization)  // Device authorization endpoint
+	oauthGroup.POST("/revoke", oauthHandler.Revoke)                             // Token revocation endpoint
+	oauthGroup.POST("/introspect", oauthHandler.Introspect)                     // Token introspection endpoint
+	oauthGroup.GET("/end_session", oauthHandler.EndSession)                     // OpenID Connect end session endpoint
+	oauthGroup.POST("/end_session", oauthHandler.EndSession)                    // OpenID Connect end session endpoint
+	oauthGroup.POST("/register", clientHandler.RegisterClient)                  // Dynamic client registration endpoint
+	oauthGroup.GET("/register/:client_id", clientHandler.GetRegistration)       // Read a registered client
+	oauthGroup.PUT("/register/:client_id", clientHandler.UpdateRegistration)    // Update a registered client
+	oauthGroup.DELETE("/register/:client_id", clientHandler.DeleteRegistration) // Delete a registered client
+	oauthGroup.GET("/userinfo", oauthHandler.UserInfo)                          // OpenID Connect userinfo endpoint
+	oauthGroup.POST("/userinfo", oauthHandler.UserInfo)                         // OpenID Connect userinfo endpoint
+
+	// OpenID Connect discovery - Public
+	e.GET("/.well-known/openid-configuration", oauthHandler.OpenIDConfiguration) // Discovery document
+	e.GET("/.well-known/jwks.json", oauthHandler.JWKS)                           // Public signing keys
 
 	// Static file serving for assets - MUST come before SPA fallback
 	e.Static("/assets", "./dist/assets")
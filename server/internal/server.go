@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/db"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features/auth"
+	"github.com/Satishcg12/CentralAuthV3/server/internal/features/signingkey"
 	"github.com/Satishcg12/CentralAuthV3/server/internal/utils"
 	"github.com/labstack/echo/v4"
 )
@@ -28,12 +31,25 @@ func InitializeServer(cfg *config.Config) *Server {
 	e := echo.New()
 	e.HideBanner = false
 
+	// Client IP addresses key rate limits and login lockouts, forwarded headers are only
+	// trusted from the configured reverse proxies
+	ipExtractor, err := newIPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Failed to set up client IP addresses: %v", err)
+	}
+	e.IPExtractor = ipExtractor
+
 	// Set up the validator using the one defined in utils package
 	e.Validator = utils.NewValidator()
 
 	// Initialize JWT configuration
 	utils.InitJWT(cfg.JWT)
 
+	// Initialize the password policy
+	if err := utils.InitPasswordPolicy(cfg.PasswordPolicy); err != nil {
+		log.Fatalf("Failed to set up password policy: %v", err)
+	}
+
 	// Connect to database
 	database, err := db.Connect(cfg.DB)
 	if err != nil {
@@ -50,6 +66,16 @@ func InitializeServer(cfg *config.Config) *Server {
 	// Create store
 	store := db.NewStore(database)
 
+	// Give the configured admin the admin role
+	if err := auth.BootstrapAdmin(context.Background(), store, cfg.AdminEmail); err != nil {
+		log.Printf("Warning: Failed to grant the admin role: %v", err)
+	}
+
+	// Load the token signing keys and keep them rotated
+	if err := signingkey.NewKeyManager(store, cfg).Start(); err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+
 	// Add store to context
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -73,6 +99,30 @@ func InitializeServer(cfg *config.Config) *Server {
 	}
 }
 
+// newIPExtractor returns how the IP address of a client is determined. Without trusted proxies
+// it is the address of the connection, otherwise the X-Forwarded-For header is followed back
+// through the trusted proxies.
+func newIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// Only the configured ranges are trusted, not every private network
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %q: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
 // StartServer starts the HTTP server in a goroutine and returns a channel that
 // will receive a signal when the server is to be shut down
 func (s *Server) StartServer() chan os.Signal {
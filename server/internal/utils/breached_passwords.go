@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// breachedLineBuffer is how much of the corpus file is read to find a line. Lines are a hash,
+// optionally followed by a count, far shorter than this.
+const breachedLineBuffer = 256
+
+var errInvalidBreachedLine = errors.New("invalid line in breached passwords file")
+
+// BreachedPasswords is a local corpus of breached passwords. The corpus is a file of SHA-1
+// hashes sorted by hash, which is binary searched on disk rather than loaded, so that the full
+// Have I Been Pwned download needs no memory.
+type BreachedPasswords struct {
+	file *os.File
+	size int64
+}
+
+// LoadBreachedPasswords opens a corpus file with a SHA-1 hash on every line, optionally followed
+// by a colon and the number of times it was seen. The lines have to be sorted by hash, like the
+// Have I Been Pwned downloads ordered by hash.
+func LoadBreachedPasswords(path string) (*BreachedPasswords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached passwords file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open breached passwords file: %w", err)
+	}
+
+	b := &BreachedPasswords{file: file, size: info.Size()}
+
+	// Reading the first line catches files in another format before the first lookup does
+	if b.size > 0 {
+		_, line, _, err := b.lineAt(0)
+		if err == nil {
+			_, err = breachedLineHash(line)
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read breached passwords file: %w", err)
+		}
+	}
+
+	return b, nil
+}
+
+// Contains reports whether the password is in the corpus
+func (b *BreachedPasswords) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	target := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// Binary search the lines starting between lo and hi, lo is always the start of a line
+	lo, hi := int64(0), b.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, line, end, err := b.lineAt(mid)
+		if err != nil {
+			return false, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		hash, err := breachedLineHash(line)
+		if err != nil {
+			return false, err
+		}
+		switch strings.Compare(hash, target) {
+		case 0:
+			return true, nil
+		case -1:
+			lo = end
+		default:
+			hi = start
+		}
+	}
+	return false, nil
+}
+
+// Size is the size of the corpus file in bytes
+func (b *BreachedPasswords) Size() int64 {
+	return b.size
+}
+
+// Close closes the corpus file
+func (b *BreachedPasswords) Close() error {
+	return b.file.Close()
+}
+
+// lineAt finds the first line starting at or after offset. It returns where the line starts,
+// its content and where the next line starts, or the file size when there is no such line.
+func (b *BreachedPasswords) lineAt(offset int64) (int64, []byte, int64, error) {
+	// The byte before offset tells whether a line starts at offset
+	readFrom := max(offset-1, 0)
+	buf := make([]byte, breachedLineBuffer)
+	n, err := b.file.ReadAt(buf, readFrom)
+	if err != nil && err != io.EOF {
+		return 0, nil, 0, err
+	}
+	buf = buf[:n]
+	atEOF := readFrom+int64(n) >= b.size
+
+	start := readFrom
+	if offset > 0 {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			if atEOF {
+				return b.size, nil, b.size, nil
+			}
+			return 0, nil, 0, errInvalidBreachedLine
+		}
+		buf = buf[i+1:]
+		start += int64(i) + 1
+	}
+	if start >= b.size {
+		return b.size, nil, b.size, nil
+	}
+
+	// The last line may not end with a line break
+	i := bytes.IndexByte(buf, '\n')
+	if i < 0 {
+		if atEOF {
+			return start, buf, b.size, nil
+		}
+		return 0, nil, 0, errInvalidBreachedLine
+	}
+	return start, buf[:i], start + int64(i) + 1, nil
+}
+
+// breachedLineHash returns the upper case hash of a corpus line
+func breachedLineHash(line []byte) (string, error) {
+	hash, _, _ := strings.Cut(strings.TrimSpace(string(line)), ":")
+	if _, err := hex.DecodeString(hash); err != nil || len(hash) != sha1.Size*2 {
+		return "", errInvalidBreachedLine
+	}
+	return strings.ToUpper(hash), nil
+}
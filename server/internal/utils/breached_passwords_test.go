@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// breachedTestHashes are the SHA-1 hashes of the test corpus passwords in sorted order
+var breachedTestHashes = []struct {
+	password string
+	hash     string
+}{
+	{"password", "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8"},
+	{"123456", "7C4A8D09CA3762AF61E59520943DC26494F8941B"},
+	{"monkey", "AB87D24BDC7452E55738DEB5F868E1F16DEA5ACE"},
+	{"dragon", "AF8978B1797B72ACFFF9595A5A2A373EC3D9106D"},
+	{"qwerty", "B1B3773A05C0ED0176787A4F1574FF0075F7521E"},
+	{"letmein", "B7A875FC1EA228B9061041B7CEC4BD3C52AB3CE3"},
+}
+
+// writeBreachedCorpus writes a corpus file with the lines joined by sep
+func writeBreachedCorpus(t *testing.T, lines []string, sep string, trailingNewline bool) *BreachedPasswords {
+	t.Helper()
+
+	content := strings.Join(lines, sep)
+	if trailingNewline && len(lines) > 0 {
+		content += sep
+	}
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := LoadBreachedPasswords(path)
+	if err != nil {
+		t.Fatalf("LoadBreachedPasswords() error = %v", err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestBreachedPasswordsContains(t *testing.T) {
+	hashes := func(format func(hash string, i int) string) []string {
+		lines := make([]string, len(breachedTestHashes))
+		for i, h := range breachedTestHashes {
+			lines[i] = format(h.hash, i)
+		}
+		return lines
+	}
+
+	corpora := []struct {
+		name            string
+		lines           []string
+		sep             string
+		trailingNewline bool
+	}{
+		{"hashes", hashes(func(hash string, _ int) string { return hash }), "\n", true},
+		{"without trailing newline", hashes(func(hash string, _ int) string { return hash }), "\n", false},
+		{"with counts", hashes(func(hash string, i int) string { return fmt.Sprintf("%s:%d", hash, i*1000+1) }), "\n", true},
+		{"with counts without trailing newline", hashes(func(hash string, i int) string { return fmt.Sprintf("%s:%d", hash, i+1) }), "\n", false},
+		{"lower case", hashes(func(hash string, _ int) string { return strings.ToLower(hash) }), "\n", true},
+		{"CRLF line breaks", hashes(func(hash string, i int) string { return fmt.Sprintf("%s:%d", hash, i+1) }), "\r\n", true},
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     bool
+	}{
+		{"first line", "password", true},
+		{"second line", "123456", true},
+		{"middle line", "dragon", true},
+		{"second to last line", "qwerty", true},
+		{"last line", "letmein", true},
+		{"before the first line", "d", false},
+		{"between lines", "correct horse battery staple", false},
+		{"after the last line", "hunter2", false},
+		{"empty password", "", false},
+	}
+
+	for _, corpus := range corpora {
+		t.Run(corpus.name, func(t *testing.T) {
+			b := writeBreachedCorpus(t, corpus.lines, corpus.sep, corpus.trailingNewline)
+			for _, tt := range tests {
+				got, err := b.Contains(tt.password)
+				if err != nil {
+					t.Fatalf("Contains(%q) error = %v", tt.password, err)
+				}
+				if got != tt.want {
+					t.Errorf("%s: Contains(%q) = %v, want %v", tt.name, tt.password, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBreachedPasswordsContainsSingleLine(t *testing.T) {
+	for _, trailingNewline := range []bool{true, false} {
+		t.Run(fmt.Sprintf("trailing newline %v", trailingNewline), func(t *testing.T) {
+			b := writeBreachedCorpus(t, []string{breachedTestHashes[2].hash}, "\n", trailingNewline)
+			for _, h := range breachedTestHashes {
+				got, err := b.Contains(h.password)
+				if err != nil {
+					t.Fatalf("Contains(%q) error = %v", h.password, err)
+				}
+				if want := h.password == breachedTestHashes[2].password; got != want {
+					t.Errorf("Contains(%q) = %v, want %v", h.password, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBreachedPasswordsContainsLargeCorpus(t *testing.T) {
+	// Enough lines that the search reads lines starting across many buffer boundaries
+	hashes := make([]string, 2000)
+	for i := range hashes {
+		sum := sha1.Sum([]byte(fmt.Sprintf("password-%d", i)))
+		hashes[i] = strings.ToUpper(hex.EncodeToString(sum[:]))
+	}
+	slices.Sort(hashes)
+
+	lines := make([]string, len(hashes))
+	for i, hash := range hashes {
+		lines[i] = fmt.Sprintf("%s:%d", hash, i+1)
+	}
+	b := writeBreachedCorpus(t, lines, "\n", false)
+
+	for i := range hashes {
+		password := fmt.Sprintf("password-%d", i)
+		if got, err := b.Contains(password); err != nil || !got {
+			t.Fatalf("Contains(%q) = %v, %v, want true", password, got, err)
+		}
+	}
+	for _, password := range []string{"password", "d", "hunter2", "password-2000"} {
+		if got, err := b.Contains(password); err != nil || got {
+			t.Errorf("Contains(%q) = %v, %v, want false", password, got, err)
+		}
+	}
+}
+
+func TestBreachedPasswordsEmptyFile(t *testing.T) {
+	b := writeBreachedCorpus(t, nil, "\n", false)
+	if got, err := b.Contains("password"); err != nil || got {
+		t.Errorf("Contains() = %v, %v, want false", got, err)
+	}
+}
+
+func TestLoadBreachedPasswordsRejectsOtherFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"plain passwords", "password\n123456\n"},
+		{"NTLM hashes", "8846F7EAEE8FB117AD06BDD830B7586C:1\n"},
+		{"line too long", strings.Repeat("A", breachedLineBuffer*2) + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "breached.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if b, err := LoadBreachedPasswords(path); err == nil {
+				b.Close()
+				t.Error("LoadBreachedPasswords() succeeded, want an error")
+			}
+		})
+	}
+}
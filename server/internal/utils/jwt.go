@@ -1,12 +1,15 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
-	"log"
+	"slices"
 	"time"
 
 	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -21,13 +24,31 @@ func InitJWT(cfg config.JWTConfig) {
 
 // AccessTokenClaims represents the claims for access tokens
 type AccessTokenClaims struct {
-	UserID        string `json:"user_id"`
-	Email         string `json:"email,omitempty"`
-	FullName      string `json:"full_name,omitempty"`
-	EmailVerified bool   `json:"email_verified,omitempty"`
+	UserID        string   `json:"user_id,omitempty"` // Only set for first-party tokens, OAuth tokens name the user as their subject
+	Email         string   `json:"email,omitempty"`
+	FullName      string   `json:"full_name,omitempty"`
+	EmailVerified bool     `json:"email_verified,omitempty"`
+	ClientID      string   `json:"client_id,omitempty"` // Set for tokens issued through the OAuth token endpoint
+	Scope         string   `json:"scope,omitempty"`
+	SessionID     string   `json:"sid,omitempty"`         // Set for tokens issued to a login session
+	AMR           []string `json:"amr,omitempty"`         // Authentication methods of the login session (RFC 8176)
+	Roles         []string `json:"roles,omitempty"`       // Roles of the user, set for tokens issued to a login session
+	Permissions   []string `json:"permissions,omitempty"` // Permissions granted by the roles
 	jwt.RegisteredClaims
 }
 
+// IsClientToken reports whether the token was issued to a client acting on its own behalf
+// through the client_credentials grant rather than to a user
+func (c *AccessTokenClaims) IsClientToken() bool {
+	return c.UserID == ""
+}
+
+// IsOAuthToken reports whether the token was issued to an OAuth client through the token endpoint,
+// for a user or for the client itself, rather than to a first-party login session
+func (c *AccessTokenClaims) IsOAuthToken() bool {
+	return c.ClientID != ""
+}
+
 // CreateAccessToken generates a JWT access token for the authenticated user with roles and permissions
 // It returns the token string, expiry time in seconds, and any error
 func CreateAccessToken(claims AccessTokenClaims) (string, int, error) {
@@ -35,42 +56,165 @@ func CreateAccessToken(claims AccessTokenClaims) (string, int, error) {
 	expiry := jwtConfig.ExpiryHours * 3600 // Convert hours to seconds
 
 	// Set the expiration time in the claims
+	// Subject, audience and jti set by the caller are kept
 	expirationTime := time.Now().Add(time.Duration(expiry) * time.Second)
-	claims.RegisteredClaims = jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(expirationTime),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	if claims.ID == "" {
+		claims.ID = uuid.NewString() // jti, lets the token be revoked before it expires
 	}
+	claims.Issuer = jwtConfig.Issuer
+	claims.ExpiresAt = jwt.NewNumericDate(expirationTime)
+	claims.IssuedAt = jwt.NewNumericDate(time.Now())
 
-	// Create the token using the claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := signToken(claims)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return tokenString, expiry, nil
+}
+
+// IDTokenClaims represents the claims of an OpenID Connect ID token
+type IDTokenClaims struct {
+	Nonce         string   `json:"nonce,omitempty"`
+	AuthTime      int64    `json:"auth_time,omitempty"`
+	AtHash        string   `json:"at_hash,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Email         string   `json:"email,omitempty"`
+	EmailVerified *bool    `json:"email_verified,omitempty"` // Only present with the email scope
+	SessionID     string   `json:"sid,omitempty"`            // Login session, identifies the session in logout tokens
+	AMR           []string `json:"amr,omitempty"`            // Authentication methods of the login session (RFC 8176)
+	jwt.RegisteredClaims
+}
+
+// CreateIDToken generates an OpenID Connect ID token. The subject and audience must be set
+// by the caller, at_hash is derived from the access token issued alongside it.
+func CreateIDToken(claims IDTokenClaims, accessToken string) (string, error) {
+	expirationTime := time.Now().Add(time.Duration(jwtConfig.ExpiryHours) * time.Hour)
+	claims.Issuer = jwtConfig.Issuer
+	claims.ExpiresAt = jwt.NewNumericDate(expirationTime)
+	claims.IssuedAt = jwt.NewNumericDate(time.Now())
+
+	// at_hash is the left half of the SHA-256 digest of the access token
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		claims.AtHash = base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+	}
+
+	return signToken(claims)
+}
+
+// BackchannelLogoutEvent is the event a logout token carries (OpenID Connect Back-Channel Logout 1.0)
+const BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// LogoutTokenClaims represents the claims of an OpenID Connect logout token
+type LogoutTokenClaims struct {
+	SessionID string         `json:"sid,omitempty"`
+	Events    map[string]any `json:"events"`
+	jwt.RegisteredClaims
+}
+
+// CreateLogoutToken generates a logout token telling a client that a user's session has
+// ended. The subject, session and audience must be set by the caller.
+func CreateLogoutToken(claims LogoutTokenClaims) (string, error) {
+	claims.ID = uuid.NewString()
+	claims.Issuer = jwtConfig.Issuer
+	claims.IssuedAt = jwt.NewNumericDate(time.Now())
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(2 * time.Minute))
+	claims.Events = map[string]any{BackchannelLogoutEvent: map[string]any{}}
+
+	// The explicit type keeps logout tokens from being confused with other tokens
+	return signTokenWithType(claims, "logout+jwt")
+}
+
+// idTokenHintClaims are the claims of an ID token hint together with the claims that only
+// access tokens and logout tokens carry, which tell those tokens apart from ID tokens
+type idTokenHintClaims struct {
+	IDTokenClaims
+	ClientID string         `json:"client_id,omitempty"`
+	Scope    string         `json:"scope,omitempty"`
+	Events   map[string]any `json:"events,omitempty"`
+}
+
+// ValidateIDTokenHint parses an ID token previously issued by this server to clientID, or to
+// any client when clientID is empty. Expired tokens are accepted since they still identify the
+// user and client (OpenID Connect RP-Initiated Logout 1.0), other tokens signed by this server
+// such as access tokens and logout tokens are not.
+func ValidateIDTokenHint(tokenString, clientID string) (*IDTokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &idTokenHintClaims{}, verificationKey, jwt.WithoutClaimsValidation())
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
 
-	log.Println(jwtConfig.Secret)
-	// Sign the token with the secret key
-	if jwtConfig.Secret == "" {
-		return "", 0, fmt.Errorf("JWT secret is not configured")
+	claims, ok := token.Claims.(*idTokenHintClaims)
+	if !ok || !token.Valid || claims.Issuer != jwtConfig.Issuer || claims.Subject == "" {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+
+	// ID tokens are always issued to a client and never carry a scope or events
+	typ, _ := token.Header["typ"].(string)
+	if typ == "logout+jwt" || claims.Events != nil || claims.ClientID != "" || claims.Scope != "" {
+		return nil, fmt.Errorf("not an ID token")
+	}
+	if len(claims.Audience) == 0 || (clientID != "" && !slices.Contains(claims.Audience, clientID)) {
+		return nil, fmt.Errorf("ID token was not issued to the client")
+	}
+
+	return &claims.IDTokenClaims, nil
+}
+
+// signToken signs the claims with the active signing key and sets its kid header
+func signToken(claims jwt.Claims) (string, error) {
+	return signTokenWithType(claims, "")
+}
+
+// signTokenWithType signs the claims like signToken and overrides the typ header when given
+func signTokenWithType(claims jwt.Claims, typ string) (string, error) {
+	key, err := GetActiveSigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	method, err := signingMethod(key.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	// Create the token using the claims
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = key.KeyID
+	if typ != "" {
+		token.Header["typ"] = typ
 	}
 
 	// Sign and get the complete encoded token as a string
-	tokenString, err := token.SignedString([]byte(jwtConfig.Secret))
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to sign token: %w", err)
+		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
 
-	return tokenString, expiry, nil
+	return tokenString, nil
+}
+
+// verificationKey selects the public key a token was signed with by its kid header
+func verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := getVerificationKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %q", kid)
+	}
+
+	// The algorithm is bound to the key, never trust the alg header on its own
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PrivateKey.Public(), nil
 }
 
 // GetTokenFromRequest extracts the JWT token from the Authorization header
 func GetUserIDFromAccessToken(tokenString string) (string, error) {
 	// Parse the token
-	token, err := jwt.ParseWithClaims(tokenString, &AccessTokenClaims{}, func(token *jwt.Token) (any, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		// Return the key for validation
-		return []byte(jwtConfig.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &AccessTokenClaims{}, verificationKey)
 
 	if err != nil {
 		return "", fmt.Errorf("invalid token: %w", err)
@@ -87,15 +231,7 @@ func GetUserIDFromAccessToken(tokenString string) (string, error) {
 // ValidateToken validates and parses a JWT token
 func ValidateToken(tokenString string) (*AccessTokenClaims, error) {
 	// Parse the token
-	token, err := jwt.ParseWithClaims(tokenString, &AccessTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		// Return the key for validation
-		return []byte(jwtConfig.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &AccessTokenClaims{}, verificationKey)
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
@@ -103,6 +239,11 @@ func ValidateToken(tokenString string) (*AccessTokenClaims, error) {
 
 	// Extract and validate claims
 	if claims, ok := token.Claims.(*AccessTokenClaims); ok && token.Valid {
+		// Tokens issued to OAuth clients for a user name the user only as their subject,
+		// client_credentials tokens name the client instead
+		if claims.UserID == "" && claims.IsOAuthToken() && claims.Subject != claims.ClientID {
+			claims.UserID = claims.Subject
+		}
 		return claims, nil
 	}
 
@@ -0,0 +1,340 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported asymmetric signing algorithms
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmES256 = "ES256"
+)
+
+// SigningKey is a private key used to sign tokens, identified by its kid
+type SigningKey struct {
+	KeyID       string
+	Algorithm   string
+	PrivateKey  crypto.Signer
+	ActivatesAt time.Time // The key signs tokens from this time until a newer key activates
+}
+
+// JWK is the JSON Web Key representation of a public key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var (
+	keysMu sync.RWMutex
+	// publishedKeys verify tokens and are published in the JWKS, indexed by kid
+	publishedKeys map[string]*SigningKey
+)
+
+// SetSigningKeys replaces the published key set. Every key is accepted for verification,
+// the most recently activated one signs new tokens.
+func SetSigningKeys(keys []*SigningKey) {
+	published := make(map[string]*SigningKey, len(keys))
+	for _, key := range keys {
+		published[key.KeyID] = key
+	}
+
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	publishedKeys = published
+}
+
+// GetActiveSigningKey returns the key currently used to sign tokens. Keys published ahead of
+// their activation time are skipped, so a pending key takes over as soon as it activates.
+func GetActiveSigningKey() (*SigningKey, error) {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+
+	now := time.Now()
+	var active *SigningKey
+	for _, key := range publishedKeys {
+		if key.ActivatesAt.After(now) {
+			continue
+		}
+		if active == nil || key.ActivatesAt.After(active.ActivatesAt) {
+			active = key
+		}
+	}
+	if active == nil {
+		return nil, errors.New("no signing key is active")
+	}
+	return active, nil
+}
+
+// getVerificationKey returns the published key with the given kid
+func getVerificationKey(kid string) (*SigningKey, bool) {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+	key, ok := publishedKeys[kid]
+	return key, ok
+}
+
+// GetJWKS returns the public keys that tokens may be verified with
+func GetJWKS() JWKS {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(publishedKeys))}
+	for _, key := range publishedKeys {
+		jwk, err := PublicJWK(key.PrivateKey.Public(), key.Algorithm)
+		if err != nil {
+			continue
+		}
+		jwk.Kid = key.KeyID
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+// GenerateSigningKey creates a new private key for the given algorithm
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch algorithm {
+	case SigningAlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case SigningAlgorithmES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	return NewSigningKey(privateKey, algorithm)
+}
+
+// EncodePrivateKey encodes a private key as a PKCS#8 PEM block
+func EncodePrivateKey(privateKey crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// DecodePrivateKey parses a PKCS#8 PEM encoded private key
+func DecodePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	privateKey, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("signing key type is not supported")
+	}
+	return privateKey, nil
+}
+
+// NewSigningKey checks that the key matches the algorithm and derives its kid
+func NewSigningKey(privateKey crypto.Signer, algorithm string) (*SigningKey, error) {
+	jwk, err := PublicJWK(privateKey.Public(), algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := jwkThumbprint(jwk)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		KeyID:      kid,
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+	}, nil
+}
+
+// PublicJWK converts a public key into its JWK representation for the given algorithm
+func PublicJWK(publicKey crypto.PublicKey, algorithm string) (JWK, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm != SigningAlgorithmRS256 {
+			return JWK{}, fmt.Errorf("an RSA key cannot be used with %s", algorithm)
+		}
+		return JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: algorithm,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		if algorithm != SigningAlgorithmES256 || key.Curve != elliptic.P256() {
+			return JWK{}, fmt.Errorf("an EC key on curve %s cannot be used with %s", key.Curve.Params().Name, algorithm)
+		}
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return JWK{}, fmt.Errorf("invalid EC key: %w", err)
+		}
+		// Uncompressed point encoding is 0x04 || X || Y
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+		return JWK{
+			Kty: "EC",
+			Use: "sig",
+			Alg: algorithm,
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+			Y:   base64.RawURLEncoding.EncodeToString(point[1+size:]),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
+// PublicKey converts a JWK back into the RSA or EC public key it represents
+func (jwk JWK) PublicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil || len(n) == 0 {
+			return nil, errors.New("invalid RSA modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		key := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		if key.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return key, nil
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+
+		size := (curve.Params().BitSize + 7) / 8
+		x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+		y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if errX != nil || errY != nil || len(x) != size || len(y) != size {
+			return nil, errors.New("invalid EC coordinates")
+		}
+
+		// Parsing the uncompressed point checks that it lies on the curve
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid EC point: %w", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+	}
+}
+
+// ParseJWKS parses a JSON Web Key Set and checks that every key in it is usable
+func ParseJWKS(data []byte) (JWKS, error) {
+	var jwks JWKS
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return JWKS{}, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	if len(jwks.Keys) == 0 {
+		return JWKS{}, errors.New("the JWKS contains no keys")
+	}
+	for _, jwk := range jwks.Keys {
+		if _, err := jwk.PublicKey(); err != nil {
+			return JWKS{}, fmt.Errorf("invalid key %q: %w", jwk.Kid, err)
+		}
+	}
+	return jwks, nil
+}
+
+// jwkThumbprint computes the RFC 7638 thumbprint of a JWK, used as its kid
+func jwkThumbprint(jwk JWK) (string, error) {
+	// The required members in lexicographic order
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		return "", fmt.Errorf("unsupported key type %s", jwk.Kty)
+	}
+
+	encoded, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// signingMethod returns the jwt signing method for an algorithm name
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case SigningAlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case SigningAlgorithmES256:
+		return jwt.SigningMethodES256, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+}
@@ -3,18 +3,58 @@ package utils
 import (
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/pbkdf2"
 )
 
+// Password hashing algorithms, as named in PHC encoded hashes
+const (
+	algorithmArgon2id     = "argon2id"
+	algorithmPBKDF2SHA256 = "pbkdf2-sha256"
+)
+
 const (
-	iterations = 600000
 	keyLength  = 32
 	saltLength = 16
+
+	// Iterations of the PBKDF2 hashes stored before hashes were PHC encoded
+	legacyPBKDF2Iterations = 600000
 )
 
+// argon2Params are the cost parameters of an argon2id hash
+type argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// passwordArgon2Params are the parameters new passwords are hashed with. Hashes with other
+// parameters are still verified and replaced on the next login, see PasswordNeedsRehash.
+var passwordArgon2Params = argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+}
+
+var errInvalidPasswordHash = errors.New("invalid password hash")
+
+// passwordHash is a decoded password hash
+type passwordHash struct {
+	algorithm  string
+	argon2     argon2Params // Set for argon2id hashes
+	iterations int          // Set for PBKDF2 hashes
+	salt       []byte
+	key        []byte
+}
+
+// Hash hashes a password with argon2id and a random salt. The result is PHC encoded, it names the
+// algorithm and its parameters: $argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
 func Hash(password string) (string, error) {
 	// Generate random salt
 	salt := make([]byte, saltLength)
@@ -22,33 +62,141 @@ func Hash(password string) (string, error) {
 		return "", fmt.Errorf("error generating salt: %w", err)
 	}
 
-	// Generate hash using PBKDF2
-	hash := pbkdf2.Key([]byte(password), salt, iterations, keyLength, sha256.New)
-
-	// Combine salt and hash, encode as base64
-	combined := append(salt, hash...)
-	encoded := base64.StdEncoding.EncodeToString(combined)
+	h := passwordHash{
+		algorithm: algorithmArgon2id,
+		argon2:    passwordArgon2Params,
+		salt:      salt,
+	}
+	h.key = h.derive(password, keyLength)
 
-	return encoded, nil
+	return h.encode(), nil
 }
 
+// ComparePasswords reports whether password matches the hash. Argon2id and PBKDF2 hashes are
+// verified, including the bare base64 PBKDF2 hashes stored before hashes were PHC encoded.
 func ComparePasswords(hashedPassword, password string) bool {
-	// Decode the stored hash
-	decoded, err := base64.StdEncoding.DecodeString(hashedPassword)
+	h, err := decodePasswordHash(hashedPassword)
 	if err != nil {
 		return false
 	}
 
-	// Split salt and hash
-	if len(decoded) < saltLength {
-		return false
+	// Compare hashes in constant time
+	return subtle.ConstantTimeCompare(h.key, h.derive(password, len(h.key))) == 1
+}
+
+// SimulatePasswordComparison takes as long as comparing a password with a hash of the current
+// parameters, so that logins of unknown email addresses cannot be told apart by their timing
+func SimulatePasswordComparison(password string) {
+	h := passwordHash{
+		algorithm: algorithmArgon2id,
+		argon2:    passwordArgon2Params,
+		salt:      make([]byte, saltLength),
 	}
-	salt := decoded[:saltLength]
-	storedHash := decoded[saltLength:]
+	h.derive(password, keyLength)
+}
+
+// PasswordNeedsRehash reports whether a hash was not created with the current algorithm and
+// parameters. The password should then be hashed again while it is at hand, after a login.
+func PasswordNeedsRehash(hashedPassword string) bool {
+	h, err := decodePasswordHash(hashedPassword)
+	if err != nil {
+		return true
+	}
+	return h.algorithm != algorithmArgon2id ||
+		h.argon2 != passwordArgon2Params ||
+		len(h.salt) < saltLength ||
+		len(h.key) != keyLength
+}
 
-	// Generate hash with provided password
-	newHash := pbkdf2.Key([]byte(password), salt, iterations, keyLength, sha256.New)
+// derive derives a key of length bytes from password with the algorithm, parameters and salt of
+// the hash
+func (h passwordHash) derive(password string, length int) []byte {
+	switch h.algorithm {
+	case algorithmArgon2id:
+		return argon2.IDKey([]byte(password), h.salt, h.argon2.Iterations, h.argon2.Memory, h.argon2.Parallelism, uint32(length))
+	case algorithmPBKDF2SHA256:
+		return pbkdf2.Key([]byte(password), h.salt, h.iterations, length, sha256.New)
+	default:
+		return nil
+	}
+}
+
+// encode formats the hash in the PHC string format
+func (h passwordHash) encode() string {
+	var params string
+	switch h.algorithm {
+	case algorithmArgon2id:
+		params = fmt.Sprintf("v=%d$m=%d,t=%d,p=%d", argon2.Version, h.argon2.Memory, h.argon2.Iterations, h.argon2.Parallelism)
+	case algorithmPBKDF2SHA256:
+		params = fmt.Sprintf("i=%d", h.iterations)
+	}
+
+	return fmt.Sprintf(
+		"$%s$%s$%s$%s",
+		h.algorithm,
+		params,
+		base64.RawStdEncoding.EncodeToString(h.salt),
+		base64.RawStdEncoding.EncodeToString(h.key),
+	)
+}
+
+// decodePasswordHash parses a PHC encoded hash, or a legacy PBKDF2 hash
+func decodePasswordHash(encoded string) (passwordHash, error) {
+	if !strings.HasPrefix(encoded, "$") {
+		return decodeLegacyPasswordHash(encoded)
+	}
+
+	var h passwordHash
+	var salt, key string
+	parts := strings.Split(encoded, "$")
+	switch {
+	case len(parts) == 6 && parts[1] == algorithmArgon2id:
+		h.algorithm = algorithmArgon2id
+
+		var version int
+		if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+			return passwordHash{}, errInvalidPasswordHash
+		}
+		_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.argon2.Memory, &h.argon2.Iterations, &h.argon2.Parallelism)
+		if err != nil || h.argon2.Iterations == 0 || h.argon2.Parallelism == 0 {
+			return passwordHash{}, errInvalidPasswordHash
+		}
+		salt, key = parts[4], parts[5]
+
+	case len(parts) == 5 && parts[1] == algorithmPBKDF2SHA256:
+		h.algorithm = algorithmPBKDF2SHA256
+
+		if _, err := fmt.Sscanf(parts[2], "i=%d", &h.iterations); err != nil || h.iterations <= 0 {
+			return passwordHash{}, errInvalidPasswordHash
+		}
+		salt, key = parts[3], parts[4]
+
+	default:
+		return passwordHash{}, errInvalidPasswordHash
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(salt); err != nil {
+		return passwordHash{}, errInvalidPasswordHash
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(key); err != nil || len(h.key) == 0 {
+		return passwordHash{}, errInvalidPasswordHash
+	}
+	return h, nil
+}
+
+// decodeLegacyPasswordHash parses the hashes stored before hashes were PHC encoded, the base64
+// encoded salt followed by a PBKDF2-SHA256 key
+func decodeLegacyPasswordHash(encoded string) (passwordHash, error) {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(decoded) <= saltLength {
+		return passwordHash{}, errInvalidPasswordHash
+	}
 
-	// Compare hashes
-	return string(storedHash) == string(newHash)
+	return passwordHash{
+		algorithm:  algorithmPBKDF2SHA256,
+		iterations: legacyPBKDF2Iterations,
+		salt:       decoded[:saltLength],
+		key:        decoded[saltLength:],
+	}, nil
 }
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Satishcg12/CentralAuthV3/server/internal/config"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	passwordPolicy    config.PasswordPolicyConfig
+	breachedPasswords *BreachedPasswords // Nil when the breached password check is disabled
+)
+
+// InitPasswordPolicy sets the rules the password validation tag checks and loads the breached
+// passwords corpus
+func InitPasswordPolicy(cfg config.PasswordPolicyConfig) error {
+	passwordPolicy = cfg
+	if breachedPasswords != nil {
+		breachedPasswords.Close()
+		breachedPasswords = nil
+	}
+
+	if cfg.BreachedPasswordsFile != "" {
+		b, err := LoadBreachedPasswords(cfg.BreachedPasswordsFile)
+		if err != nil {
+			return err
+		}
+		breachedPasswords = b
+		log.Printf("Checking passwords against %s (%d bytes)", cfg.BreachedPasswordsFile, b.Size())
+	}
+	return nil
+}
+
+// PasswordContext describes the user a new password is for. The rules that need it are skipped
+// when validating without one.
+type PasswordContext struct {
+	Email          string
+	Name           string
+	PasswordHashes []string // Hashes of the current and previous passwords, which cannot be reused
+}
+
+type passwordContextKey struct{}
+
+// WithPasswordContext returns a context carrying the user a new password is for, see ValidateCtx
+func WithPasswordContext(ctx context.Context, pc PasswordContext) context.Context {
+	return context.WithValue(ctx, passwordContextKey{}, pc)
+}
+
+// registerPasswordValidations registers the password policy rules. The password tag checks all
+// of them, cheapest first, the rule that fails is the actual tag of the validation error.
+func registerPasswordValidations(v *validator.Validate) {
+	v.RegisterValidation("password_length", validatePasswordLength)
+	v.RegisterValidation("password_classes", validatePasswordClasses)
+	v.RegisterValidationCtx("password_personal", validatePasswordPersonal)
+	v.RegisterValidation("password_breached", validatePasswordBreached)
+	v.RegisterValidationCtx("password_history", validatePasswordHistory)
+	v.RegisterAlias("password", "password_length,password_classes,password_personal,password_breached,password_history")
+}
+
+func validatePasswordLength(fl validator.FieldLevel) bool {
+	length := utf8.RuneCountInString(fl.Field().String())
+	return length >= passwordPolicy.MinLength && (passwordPolicy.MaxLength <= 0 || length <= passwordPolicy.MaxLength)
+}
+
+func validatePasswordClasses(fl validator.FieldLevel) bool {
+	var lower, upper, digit, symbol bool
+	for _, r := range fl.Field().String() {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	return classes >= passwordPolicy.MinCharacterClasses
+}
+
+// validatePasswordPersonal refuses passwords containing the email address, its local part or a
+// part of the name of the user. Parts shorter than three characters are too common to refuse.
+func validatePasswordPersonal(ctx context.Context, fl validator.FieldLevel) bool {
+	pc, ok := ctx.Value(passwordContextKey{}).(PasswordContext)
+	if !ok || !passwordPolicy.DisallowPersonalInfo {
+		return true
+	}
+
+	password := strings.ToLower(fl.Field().String())
+	localPart, _, _ := strings.Cut(pc.Email, "@")
+	for _, part := range append([]string{pc.Email, localPart}, strings.Fields(pc.Name)...) {
+		part = strings.ToLower(part)
+		if utf8.RuneCountInString(part) >= 3 && strings.Contains(password, part) {
+			return false
+		}
+	}
+	return true
+}
+
+func validatePasswordHistory(ctx context.Context, fl validator.FieldLevel) bool {
+	pc, ok := ctx.Value(passwordContextKey{}).(PasswordContext)
+	if !ok {
+		return true
+	}
+
+	for _, hash := range pc.PasswordHashes {
+		if ComparePasswords(hash, fl.Field().String()) {
+			return false
+		}
+	}
+	return true
+}
+
+// validatePasswordBreached refuses passwords in the breached passwords corpus. A corpus that
+// cannot be read does not keep users from setting passwords, the error is logged.
+func validatePasswordBreached(fl validator.FieldLevel) bool {
+	if breachedPasswords == nil {
+		return true
+	}
+
+	breached, err := breachedPasswords.Contains(fl.Field().String())
+	if err != nil {
+		log.Printf("Failed to check breached passwords: %v", err)
+		return true
+	}
+	return !breached
+}
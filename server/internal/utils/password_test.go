@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+const testPassword = "correct horse battery staple"
+
+func TestHashAndComparePasswords(t *testing.T) {
+	hash, err := Hash(testPassword)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	if !ComparePasswords(hash, testPassword) {
+		t.Error("ComparePasswords() = false for the hashed password")
+	}
+	if ComparePasswords(hash, testPassword+"!") {
+		t.Error("ComparePasswords() = true for another password")
+	}
+	if PasswordNeedsRehash(hash) {
+		t.Error("PasswordNeedsRehash() = true for a new hash")
+	}
+
+	other, err := Hash(testPassword)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if other == hash {
+		t.Error("Hash() returned the same hash twice, the salt is not random")
+	}
+}
+
+func TestPasswordHashEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		hash passwordHash
+		want string
+	}{
+		{
+			name: "argon2id",
+			hash: passwordHash{
+				algorithm: algorithmArgon2id,
+				argon2:    argon2Params{Memory: 65536, Iterations: 3, Parallelism: 4},
+				salt:      []byte("saltsaltsaltsalt"),
+				key:       []byte("key"),
+			},
+			want: "$argon2id$v=19$m=65536,t=3,p=4$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		},
+		{
+			name: "pbkdf2-sha256",
+			hash: passwordHash{
+				algorithm:  algorithmPBKDF2SHA256,
+				iterations: 1000,
+				salt:       []byte("saltsaltsaltsalt"),
+				key:        []byte("key"),
+			},
+			want: "$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.hash.encode()
+			if encoded != tt.want {
+				t.Fatalf("encode() = %q, want %q", encoded, tt.want)
+			}
+
+			decoded, err := decodePasswordHash(encoded)
+			if err != nil {
+				t.Fatalf("decodePasswordHash(%q) error = %v", encoded, err)
+			}
+			if decoded.algorithm != tt.hash.algorithm || decoded.argon2 != tt.hash.argon2 || decoded.iterations != tt.hash.iterations ||
+				!bytes.Equal(decoded.salt, tt.hash.salt) || !bytes.Equal(decoded.key, tt.hash.key) {
+				t.Errorf("decodePasswordHash(%q) = %+v, want %+v", encoded, decoded, tt.hash)
+			}
+		})
+	}
+}
+
+func TestDecodePasswordHashRejectsMalformedHashes(t *testing.T) {
+	tests := []string{
+		"",
+		"$",
+		"$argon2i$v=19$m=65536,t=3,p=4$c2FsdA$a2V5",
+		"$argon2id$v=16$m=65536,t=3,p=4$c2FsdA$a2V5",
+		"$argon2id$m=65536,t=3,p=4$c2FsdA$a2V5",
+		"$argon2id$v=19$m=65536,t=0,p=4$c2FsdA$a2V5",
+		"$argon2id$v=19$m=65536,t=3,p=0$c2FsdA$a2V5",
+		"$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$",
+		"$argon2id$v=19$m=65536,t=3,p=4$c2FsdA==$a2V5",
+		"$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5$extra",
+		"$pbkdf2-sha256$i=0$c2FsdA$a2V5",
+		"$pbkdf2-sha256$i=-1$c2FsdA$a2V5",
+		"$pbkdf2-sha256$c2FsdA$a2V5",
+		"$bcrypt$10$c2FsdA$a2V5",
+		"not base64!",
+		"AAECAwQFBgcICQoLDA0ODw==", // A legacy hash needs a key after the salt
+	}
+
+	for _, encoded := range tests {
+		t.Run(encoded, func(t *testing.T) {
+			if _, err := decodePasswordHash(encoded); err == nil {
+				t.Errorf("decodePasswordHash(%q) succeeded, want an error", encoded)
+			}
+			if ComparePasswords(encoded, testPassword) {
+				t.Errorf("ComparePasswords(%q) = true", encoded)
+			}
+		})
+	}
+}
+
+func TestComparePasswordsWithPBKDF2Hashes(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+	}{
+		// The salt is the bytes 0 to 15, the key PBKDF2-SHA256 with 600000 iterations
+		{"legacy hash", "AAECAwQFBgcICQoLDA0OD+8XcUTuyUIMvBCT0qizRKkrxQbQ1OycAo3Rn4Mk2MHm", testPassword, true},
+		{"legacy hash with another password", "AAECAwQFBgcICQoLDA0OD+8XcUTuyUIMvBCT0qizRKkrxQbQ1OycAo3Rn4Mk2MHm", "Tr0ub4dor&3", false},
+		{"PHC hash", "$pbkdf2-sha256$i=1000$AAECAwQFBgcICQoLDA0ODw$ppsXnjrdPB4KryJ6DrOqKqhkWrhv7PbKAMF1Eml8cZ4", testPassword, true},
+		{"PHC hash with other iterations", "$pbkdf2-sha256$i=1001$AAECAwQFBgcICQoLDA0ODw$ppsXnjrdPB4KryJ6DrOqKqhkWrhv7PbKAMF1Eml8cZ4", testPassword, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComparePasswords(tt.hash, tt.password); got != tt.want {
+				t.Errorf("ComparePasswords() = %v, want %v", got, tt.want)
+			}
+			if !PasswordNeedsRehash(tt.hash) {
+				t.Error("PasswordNeedsRehash() = false for a PBKDF2 hash")
+			}
+		})
+	}
+}
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{"current parameters", "$argon2id$v=19$m=65536,t=3,p=4$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U", false},
+		{"less memory", "$argon2id$v=19$m=32768,t=3,p=4$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U", true},
+		{"short salt", "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U", true},
+		{"short key", "$argon2id$v=19$m=65536,t=3,p=4$c2FsdHNhbHRzYWx0c2FsdA$a2V5", true},
+		{"malformed", "$argon2id$", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PasswordNeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("PasswordNeedsRehash(%q) = %v, want %v", tt.hash, got, tt.want)
+			}
+		})
+	}
+}
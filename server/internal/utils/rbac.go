@@ -0,0 +1,24 @@
+package utils
+
+import "slices"
+
+// Roles the server relies on, they are created by the migrations
+const (
+	RoleAdmin = "admin" // Given to the configured admin email, holds every permission
+)
+
+// Permissions the API checks, they are created by the migrations
+const (
+	PermissionClientsRead  = "clients:read"  // View the registered OAuth clients
+	PermissionClientsWrite = "clients:write" // Create, update and delete OAuth clients
+)
+
+// HasRole reports whether the token was issued to a user with the role
+func (c *AccessTokenClaims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+// HasPermission reports whether the roles of the user grant the permission
+func (c *AccessTokenClaims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
@@ -22,6 +22,7 @@ const (
 	StatusCodeForbidden          StatusCode = http.StatusForbidden
 	StatusCodeNotFound           StatusCode = http.StatusNotFound
 	StatusCodeConflict           StatusCode = http.StatusConflict
+	StatusCodeTooManyRequests    StatusCode = http.StatusTooManyRequests
 	StatusCodeInternalError      StatusCode = http.StatusInternalServerError
 	StatusCodeServiceUnavailable StatusCode = http.StatusServiceUnavailable
 )
@@ -42,6 +43,29 @@ const (
 	ErrorCodeServiceUnavailable ErrorCode = "service_unavailable"
 	ErrorCodeTokenExpired       ErrorCode = "token_expired"
 	ErrorCodeRateLimitExceeded  ErrorCode = "rate_limit_exceeded"
+	ErrorCodeEmailNotVerified   ErrorCode = "email_not_verified"
+	ErrorCodeMFARequired        ErrorCode = "mfa_required"
+)
+
+type OAuthErrorCode string
+
+// OAuth 2.0 error codes as defined in RFC 6749
+const (
+	OAuthErrorInvalidRequest          OAuthErrorCode = "invalid_request"
+	OAuthErrorInvalidClient           OAuthErrorCode = "invalid_client"
+	OAuthErrorInvalidGrant            OAuthErrorCode = "invalid_grant"
+	OAuthErrorUnauthorizedClient      OAuthErrorCode = "unauthorized_client"
+	OAuthErrorUnsupportedGrantType    OAuthErrorCode = "unsupported_grant_type"
+	OAuthErrorInvalidScope            OAuthErrorCode = "invalid_scope"
+	OAuthErrorAccessDenied            OAuthErrorCode = "access_denied"
+	OAuthErrorUnsupportedResponseType OAuthErrorCode = "unsupported_response_type"
+	OAuthErrorServerError             OAuthErrorCode = "server_error"
+	OAuthErrorInvalidToken            OAuthErrorCode = "invalid_token"           // RFC 6750
+	OAuthErrorAuthorizationPending    OAuthErrorCode = "authorization_pending"   // RFC 8628
+	OAuthErrorSlowDown                OAuthErrorCode = "slow_down"               // RFC 8628
+	OAuthErrorExpiredToken            OAuthErrorCode = "expired_token"           // RFC 8628
+	OAuthErrorInvalidRedirectURI      OAuthErrorCode = "invalid_redirect_uri"    // RFC 7591
+	OAuthErrorInvalidClientMetadata   OAuthErrorCode = "invalid_client_metadata" // RFC 7591
 )
 
 type Status string
@@ -62,6 +86,12 @@ type APIResponse struct {
 	Timestamp string    `json:"timestamp"`
 }
 
+// OAuthErrorResponse defines the error structure mandated by RFC 6749 for OAuth endpoints
+type OAuthErrorResponse struct {
+	Error            OAuthErrorCode `json:"error"`
+	ErrorDescription string         `json:"error_description,omitempty"`
+}
+
 // APIError defines the standard error structure
 type APIError struct {
 	Code        ErrorCode `json:"code"`
@@ -119,3 +149,14 @@ func RespondWithInternalError(c echo.Context, message string, err error) error {
 		nil,
 	)
 }
+
+// RespondWithOAuthError sends an RFC 6749 error response. OAuth clients expect this
+// shape instead of the standard APIResponse envelope.
+func RespondWithOAuthError(c echo.Context, statusCode StatusCode, errorCode OAuthErrorCode, description string) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	c.Response().Header().Set("Pragma", "no-cache")
+	return c.JSON(int(statusCode), OAuthErrorResponse{
+		Error:            errorCode,
+		ErrorDescription: description,
+	})
+}
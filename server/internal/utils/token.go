@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateSecureToken generates a URL-safe random token from the given number of
+// cryptographically secure random bytes
+func GenerateSecureToken(byteLength int) (string, error) {
+	if byteLength <= 0 {
+		return "", fmt.Errorf("byte length must be greater than 0")
+	}
+
+	b := make([]byte, byteLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex encoded SHA-256 digest of a token so that
+// only the digest has to be stored in the database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
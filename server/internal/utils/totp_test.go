@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	// The SHA-1 secret of the RFC 6238 test vectors, the code 081804 belongs to the time step
+	// from 1111111080 to 1111111109
+	secret := []byte("12345678901234567890")
+	const (
+		code = "081804"
+		step = 37037036
+	)
+
+	tests := []struct {
+		name string
+		code string
+		at   int64
+		want bool
+	}{
+		{"start of the step", code, 1111111080, true},
+		{"end of the step", code, 1111111109, true},
+		{"start of the previous step", code, 1111111050, true},
+		{"before the previous step", code, 1111111049, false},
+		{"end of the next step", code, 1111111139, true},
+		{"after the next step", code, 1111111140, false},
+		{"wrong code", "081805", 1111111080, false},
+		{"too short", "81804", 1111111080, false},
+		{"too long", "0081804", 1111111080, false},
+		{"empty", "", 1111111080, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := ValidateTOTP(secret, tt.code, time.Unix(tt.at, 0))
+			if ok != tt.want {
+				t.Fatalf("ValidateTOTP(%q, %d) = %v, want %v", tt.code, tt.at, ok, tt.want)
+			}
+			if ok && gotStep != step {
+				t.Errorf("ValidateTOTP(%q, %d) step = %d, want %d", tt.code, tt.at, gotStep, step)
+			}
+		})
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to six digits
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		at   int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		if got := totpCode(secret, tt.at/30); got != tt.want {
+			t.Errorf("totpCode(%d) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
 	"strings"
 
@@ -16,7 +19,12 @@ type CustomValidator struct {
 
 // Validate performs validation and returns an error if validation fails
 func (cv *CustomValidator) Validate(i interface{}) error {
-	if err := cv.validator.Struct(i); err != nil {
+	return cv.ValidateCtx(context.Background(), i)
+}
+
+// ValidateCtx performs validation like Validate, passing ctx to the validations that use it
+func (cv *CustomValidator) ValidateCtx(ctx context.Context, i interface{}) error {
+	if err := cv.validator.StructCtx(ctx, i); err != nil {
 		// Return a validation error with the original validation errors preserved
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
 			// Store the original validation errors in the Internal field
@@ -28,6 +36,15 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return nil
 }
 
+// ValidateCtx validates a request like c.Validate, passing ctx to the validations that use it,
+// such as the password rules that depend on the user (see WithPasswordContext)
+func ValidateCtx(c echo.Context, ctx context.Context, i interface{}) error {
+	if cv, ok := c.Echo().Validator.(*CustomValidator); ok {
+		return cv.ValidateCtx(ctx, i)
+	}
+	return c.Validate(i)
+}
+
 // NewValidator creates a new validator instance
 func NewValidator() *CustomValidator {
 	v := validator.New()
@@ -53,6 +70,33 @@ func NewValidator() *CustomValidator {
 func RegisterCustomValidations(v *validator.Validate) {
 	// Add custom validations here if needed
 	// Example: v.RegisterValidation("custom_tag", customValidationFunc)
+	registerPasswordValidations(v)
+	v.RegisterValidation("redirect_uri", func(fl validator.FieldLevel) bool {
+		return IsValidRedirectURI(fl.Field().String())
+	})
+}
+
+// IsValidRedirectURI reports whether a URI users may be sent back to a client at is absolute and
+// free of a fragment. Only https URIs, http URIs of the loopback interface and private-use
+// reverse domain name schemes of native apps are allowed (RFC 8252 section 7), so that a
+// client cannot register URIs like javascript: or data: that run in the browser.
+func IsValidRedirectURI(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || !u.IsAbs() || u.Fragment != "" || len(value) > 2048 {
+		return false
+	}
+
+	switch u.Scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		host := u.Hostname()
+		ip := net.ParseIP(host)
+		return host == "localhost" || (ip != nil && ip.IsLoopback())
+	default:
+		// Private-use schemes are a reversed domain name the app's developer controls
+		return strings.Contains(u.Scheme, ".")
+	}
 }
 
 // FormatValidationErrors formats validation errors into a user-friendly map
@@ -62,9 +106,10 @@ func FormatValidationErrors(validationErrs validator.ValidationErrors) map[strin
 	for _, e := range validationErrs {
 		field := strings.ToLower(e.Field())
 		fmt.Printf("Field: %s, Tag: %s, Param: %s\n", field, e.Tag(), e.Param())
-		// Create friendly error message based on the validation tag
+		// Create friendly error message based on the validation tag, the actual tag names the
+		// rule of an alias that failed
 		var message string
-		switch e.Tag() {
+		switch e.ActualTag() {
 		case "required":
 			message = fmt.Sprintf("%s is required", field)
 		case "email":
@@ -73,6 +118,22 @@ func FormatValidationErrors(validationErrs validator.ValidationErrors) map[strin
 			message = fmt.Sprintf("%s must be at least %s characters long", field, e.Param())
 		case "max":
 			message = fmt.Sprintf("%s must be at most %s characters long", field, e.Param())
+		case "password_length":
+			if passwordPolicy.MaxLength > 0 {
+				message = fmt.Sprintf("%s must be %d to %d characters long", field, passwordPolicy.MinLength, passwordPolicy.MaxLength)
+			} else {
+				message = fmt.Sprintf("%s must be at least %d characters long", field, passwordPolicy.MinLength)
+			}
+		case "password_classes":
+			message = fmt.Sprintf("%s must mix at least %d of lowercase letters, uppercase letters, digits and symbols", field, passwordPolicy.MinCharacterClasses)
+		case "password_personal":
+			message = fmt.Sprintf("%s must not contain your email address or name", field)
+		case "password_breached":
+			message = fmt.Sprintf("%s has appeared in a data breach, please choose a different one", field)
+		case "password_history":
+			message = fmt.Sprintf("%s must not be one of your last %d passwords", field, passwordPolicy.History)
+		case "redirect_uri":
+			message = fmt.Sprintf("%s must be an https URL, an http URL of the loopback interface or a reverse domain name scheme", field)
 		case "eqfield":
 			fieldName := strings.ToLower(e.Param())
 			message = fmt.Sprintf("%s must be equal to %s", field, fieldName)
@@ -0,0 +1,88 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Authenticator data flags
+const (
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagAttestedCredentialData byte = 0x40
+	flagExtensionData          byte = 0x80
+)
+
+// maxCredentialIDLength is the longest credential ID the WebAuthn spec allows
+const maxCredentialIDLength = 1023
+
+// authenticatorData is the data an authenticator signs in both ceremonies
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+
+	// Only present in registrations
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte // COSE_Key
+}
+
+// parseAuthenticatorData decodes authenticator data, rejecting trailing bytes
+func parseAuthenticatorData(b []byte) (*authenticatorData, error) {
+	if len(b) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+	data := &authenticatorData{
+		rpIDHash:  b[:32],
+		flags:     b[32],
+		signCount: binary.BigEndian.Uint32(b[33:37]),
+	}
+	rest := b[37:]
+
+	if data.flags&flagAttestedCredentialData != 0 {
+		if len(rest) < 18 {
+			return nil, errors.New("attested credential data is too short")
+		}
+		data.aaguid = rest[:16]
+		idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLength == 0 || idLength > maxCredentialIDLength || idLength > len(rest) {
+			return nil, errors.New("invalid credential ID length")
+		}
+		data.credentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		// The public key is the only part without a length prefix, its end is found by decoding it
+		_, afterKey, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, err
+		}
+		data.publicKey = rest[:len(rest)-len(afterKey)]
+		rest = afterKey
+	}
+
+	if data.flags&flagExtensionData != 0 {
+		extensions, afterExtensions, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := extensions.(map[any]any); !ok {
+			return nil, errors.New("extension data is not a map")
+		}
+		rest = afterExtensions
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("unexpected data after the authenticator data")
+	}
+	return data, nil
+}
+
+func (d *authenticatorData) userPresent() bool {
+	return d.flags&flagUserPresent != 0
+}
+
+func (d *authenticatorData) userVerified() bool {
+	return d.flags&flagUserVerified != 0
+}
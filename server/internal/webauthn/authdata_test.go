@@ -0,0 +1,135 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+)
+
+// buildAuthenticatorData encodes authenticator data with the given flags, sign count and the
+// attested credential data and extensions that follow them
+func buildAuthenticatorData(flags byte, signCount uint32, rest ...[]byte) []byte {
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+	b := append(rpIDHash[:], flags)
+	b = binary.BigEndian.AppendUint32(b, signCount)
+	for _, part := range rest {
+		b = append(b, part...)
+	}
+	return b
+}
+
+// attestedCredentialData encodes an AAGUID, a credential ID and its public key
+func attestedCredentialData(credentialID, publicKey []byte) []byte {
+	b := bytes.Repeat([]byte{0xaa}, 16)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(credentialID)))
+	b = append(b, credentialID...)
+	return append(b, publicKey...)
+}
+
+func TestParseAuthenticatorData(t *testing.T) {
+	publicKey := okpKey(bytes.Repeat([]byte{0x01}, 32))
+	credentialID := []byte("credential-id")
+	extensions := []byte{0xa1, 0x63, 'c', 'r', 'p', 0xf5} // {"crp": true}
+
+	tests := []struct {
+		name         string
+		data         []byte
+		signCount    uint32
+		userPresent  bool
+		userVerified bool
+		credentialID []byte
+		publicKey    []byte
+	}{
+		{
+			name:        "assertion",
+			data:        buildAuthenticatorData(flagUserPresent, 42),
+			signCount:   42,
+			userPresent: true,
+		},
+		{
+			name:         "user verified assertion",
+			data:         buildAuthenticatorData(flagUserPresent|flagUserVerified, 0),
+			userPresent:  true,
+			userVerified: true,
+		},
+		{
+			name:         "registration",
+			data:         buildAuthenticatorData(flagUserPresent|flagAttestedCredentialData, 1, attestedCredentialData(credentialID, publicKey)),
+			signCount:    1,
+			userPresent:  true,
+			credentialID: credentialID,
+			publicKey:    publicKey,
+		},
+		{
+			name:         "registration with extensions",
+			data:         buildAuthenticatorData(flagUserPresent|flagAttestedCredentialData|flagExtensionData, 1, attestedCredentialData(credentialID, publicKey), extensions),
+			signCount:    1,
+			userPresent:  true,
+			credentialID: credentialID,
+			publicKey:    publicKey,
+		},
+		{
+			name:        "assertion with extensions",
+			data:        buildAuthenticatorData(flagUserPresent|flagExtensionData, 7, extensions),
+			signCount:   7,
+			userPresent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAuthenticatorData(tt.data)
+			if err != nil {
+				t.Fatalf("parseAuthenticatorData() error = %v", err)
+			}
+			if !bytes.Equal(got.rpIDHash, tt.data[:32]) {
+				t.Errorf("rpIDHash = %x, want %x", got.rpIDHash, tt.data[:32])
+			}
+			if got.signCount != tt.signCount {
+				t.Errorf("signCount = %d, want %d", got.signCount, tt.signCount)
+			}
+			if got.userPresent() != tt.userPresent || got.userVerified() != tt.userVerified {
+				t.Errorf("userPresent, userVerified = %v, %v, want %v, %v", got.userPresent(), got.userVerified(), tt.userPresent, tt.userVerified)
+			}
+			if !bytes.Equal(got.credentialID, tt.credentialID) {
+				t.Errorf("credentialID = %q, want %q", got.credentialID, tt.credentialID)
+			}
+			if !bytes.Equal(got.publicKey, tt.publicKey) {
+				t.Errorf("publicKey = %x, want %x", got.publicKey, tt.publicKey)
+			}
+		})
+	}
+}
+
+func TestParseAuthenticatorDataRejectsInvalidData(t *testing.T) {
+	publicKey := okpKey(bytes.Repeat([]byte{0x01}, 32))
+	attested := flagUserPresent | flagAttestedCredentialData
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"too short", buildAuthenticatorData(flagUserPresent, 0)[:36]},
+		{"trailing bytes", buildAuthenticatorData(flagUserPresent, 0, []byte{0x00})},
+		{"attested credential data missing", buildAuthenticatorData(attested, 0)},
+		{"attested credential data too short", buildAuthenticatorData(attested, 0, bytes.Repeat([]byte{0xaa}, 17))},
+		{"empty credential ID", buildAuthenticatorData(attested, 0, attestedCredentialData(nil, publicKey))},
+		{"credential ID too long", buildAuthenticatorData(attested, 0, attestedCredentialData(make([]byte, maxCredentialIDLength+1), publicKey))},
+		{"credential ID longer than the data", buildAuthenticatorData(attested, 0, attestedCredentialData([]byte("id"), nil)[:19])},
+		{"public key missing", buildAuthenticatorData(attested, 0, attestedCredentialData([]byte("id"), nil))},
+		{"public key truncated", buildAuthenticatorData(attested, 0, attestedCredentialData([]byte("id"), publicKey[:len(publicKey)-1]))},
+		{"extensions missing", buildAuthenticatorData(flagUserPresent|flagExtensionData, 0)},
+		{"extensions not a map", buildAuthenticatorData(flagUserPresent|flagExtensionData, 0, []byte{0x01})},
+		{"trailing bytes after extensions", buildAuthenticatorData(flagUserPresent|flagExtensionData, 0, []byte{0xa0, 0x00})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseAuthenticatorData(tt.data); err == nil {
+				t.Error("parseAuthenticatorData() succeeded, want an error")
+			}
+		})
+	}
+}
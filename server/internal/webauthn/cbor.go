@@ -0,0 +1,156 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxCBORDepth limits the nesting of decoded CBOR, authenticators never nest deeper than a few levels
+const maxCBORDepth = 16
+
+var errCBORTruncated = errors.New("cbor: unexpected end of data")
+
+// decodeCBOR decodes the first CBOR (RFC 8949) data item of b and returns it together with the
+// bytes that follow it. Only the subset authenticators produce is supported: integers, byte and
+// text strings, arrays, maps with integer or text keys, tags and simple values. Indefinite
+// lengths and floats are rejected, CTAP2 requires canonical encoding without them.
+//
+// Integers decode to int64, byte strings to []byte, text strings to string, arrays to []any and
+// maps to map[any]any.
+func decodeCBOR(b []byte) (any, []byte, error) {
+	return decodeCBORItem(b, 0)
+}
+
+func decodeCBORItem(b []byte, depth int) (any, []byte, error) {
+	if depth > maxCBORDepth {
+		return nil, nil, errors.New("cbor: nesting too deep")
+	}
+	if len(b) == 0 {
+		return nil, nil, errCBORTruncated
+	}
+
+	major, info := b[0]>>5, b[0]&0x1f
+	b = b[1:]
+
+	// Simple values carry no argument
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, b, nil
+		case 21:
+			return true, b, nil
+		case 22, 23: // null and undefined
+			return nil, b, nil
+		default:
+			return nil, nil, fmt.Errorf("cbor: unsupported simple value %d", info)
+		}
+	}
+
+	arg, b, err := readCBORArgument(info, b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0: // unsigned integer
+		if arg > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return int64(arg), b, nil
+	case 1: // negative integer
+		if arg > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(arg), b, nil
+	case 2: // byte string
+		if arg > uint64(len(b)) {
+			return nil, nil, errCBORTruncated
+		}
+		return b[:arg:arg], b[arg:], nil
+	case 3: // text string
+		if arg > uint64(len(b)) {
+			return nil, nil, errCBORTruncated
+		}
+		return string(b[:arg]), b[arg:], nil
+	case 4: // array
+		// Every item takes at least a byte, which bounds the allocation
+		if arg > uint64(len(b)) {
+			return nil, nil, errCBORTruncated
+		}
+		items := make([]any, 0, arg)
+		for range arg {
+			var item any
+			item, b, err = decodeCBORItem(b, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, b, nil
+	case 5: // map
+		if arg > uint64(len(b))/2 {
+			return nil, nil, errCBORTruncated
+		}
+		m := make(map[any]any, arg)
+		for range arg {
+			var key, value any
+			key, b, err = decodeCBORItem(b, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			if _, ok := m[key]; ok {
+				return nil, nil, fmt.Errorf("cbor: duplicate map key %v", key)
+			}
+			value, b, err = decodeCBORItem(b, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			m[key] = value
+		}
+		return m, b, nil
+	default: // tag, the tagged item is returned as is
+		return decodeCBORItem(b, depth+1)
+	}
+}
+
+// readCBORArgument reads the argument following the initial byte of a data item
+func readCBORArgument(info byte, b []byte) (uint64, []byte, error) {
+	var size int
+	switch {
+	case info < 24:
+		return uint64(info), b, nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, nil, errors.New("cbor: indefinite lengths are not supported")
+	}
+	if len(b) < size {
+		return 0, nil, errCBORTruncated
+	}
+
+	var arg uint64
+	switch size {
+	case 1:
+		arg = uint64(b[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(b))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(b))
+	case 8:
+		arg = binary.BigEndian.Uint64(b)
+	}
+	return arg, b[size:], nil
+}
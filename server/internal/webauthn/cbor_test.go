@@ -0,0 +1,87 @@
+package webauthn
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want any
+		rest []byte
+	}{
+		{"zero", []byte{0x00}, int64(0), nil},
+		{"small unsigned", []byte{0x17}, int64(23), nil},
+		{"one byte unsigned", []byte{0x18, 0x18}, int64(24), nil},
+		{"two byte unsigned", []byte{0x19, 0x01, 0x00}, int64(256), nil},
+		{"four byte unsigned", []byte{0x1a, 0x00, 0x01, 0x00, 0x00}, int64(65536), nil},
+		{"eight byte unsigned", []byte{0x1b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, int64(1<<63 - 1), nil},
+		{"negative", []byte{0x20}, int64(-1), nil},
+		{"COSE RS256", []byte{0x39, 0x01, 0x00}, int64(-257), nil},
+		{"byte string", []byte{0x43, 0x01, 0x02, 0x03}, []byte{0x01, 0x02, 0x03}, nil},
+		{"empty byte string", []byte{0x40}, []byte{}, nil},
+		{"text string", []byte{0x63, 'a', 'l', 'g'}, "alg", nil},
+		{"array", []byte{0x82, 0x01, 0x20}, []any{int64(1), int64(-1)}, nil},
+		{"map with integer and text keys", []byte{0xa2, 0x01, 0x02, 0x61, 'x', 0xf5}, map[any]any{int64(1): int64(2), "x": true}, nil},
+		{"false", []byte{0xf4}, false, nil},
+		{"true", []byte{0xf5}, true, nil},
+		{"null", []byte{0xf6}, nil, nil},
+		{"tagged item", []byte{0xc0, 0x61, 'a'}, "a", nil},
+		{"trailing bytes are returned", []byte{0x01, 0x02, 0x03}, int64(1), []byte{0x02, 0x03}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rest, err := decodeCBOR(tt.in)
+			if err != nil {
+				t.Fatalf("decodeCBOR(%x) error = %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeCBOR(%x) = %#v, want %#v", tt.in, got, tt.want)
+			}
+			if !bytes.Equal(rest, tt.rest) {
+				t.Errorf("decodeCBOR(%x) rest = %x, want %x", tt.in, rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestDecodeCBORRejectsInvalidData(t *testing.T) {
+	deep := bytes.Repeat([]byte{0x81}, maxCBORDepth+2)
+	deep = append(deep, 0x00)
+
+	tests := []struct {
+		name string
+		in   []byte
+	}{
+		{"empty", nil},
+		{"truncated argument", []byte{0x19, 0x01}},
+		{"truncated byte string", []byte{0x43, 0x01, 0x02}},
+		{"truncated text string", []byte{0x63, 'a'}},
+		{"truncated array", []byte{0x82, 0x01}},
+		{"array longer than the data", []byte{0x9a, 0xff, 0xff, 0xff, 0xff}},
+		{"map longer than the data", []byte{0xba, 0xff, 0xff, 0xff, 0xff, 0x01}},
+		{"map without value", []byte{0xa1, 0x01}},
+		{"unsigned integer overflow", []byte{0x1b, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{"negative integer overflow", []byte{0x3b, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{"indefinite length", []byte{0x5f, 0x41, 0x01, 0xff}},
+		{"reserved argument", []byte{0x1c}},
+		{"float", []byte{0xf9, 0x3c, 0x00}},
+		{"unassigned simple value", []byte{0xf0}},
+		{"byte string map key", []byte{0xa1, 0x41, 0x01, 0x01}},
+		{"array map key", []byte{0xa1, 0x80, 0x01}},
+		{"duplicate map key", []byte{0xa2, 0x01, 0x01, 0x01, 0x02}},
+		{"nesting too deep", deep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _, err := decodeCBOR(tt.in); err == nil {
+				t.Errorf("decodeCBOR(%x) = %#v, want an error", tt.in, got)
+			}
+		})
+	}
+}
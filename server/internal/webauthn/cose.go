@@ -0,0 +1,130 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithms (RFC 9053) new credentials can use, in order of preference
+const (
+	AlgES256 int64 = -7   // ECDSA with P-256 and SHA-256
+	AlgEdDSA int64 = -8   // Ed25519
+	AlgRS256 int64 = -257 // RSASSA-PKCS1-v1_5 with SHA-256
+)
+
+// COSE key parameters (RFC 9052)
+const (
+	coseKeyType  int64 = 1
+	coseKeyAlg   int64 = 3
+	coseKeyCrv   int64 = -1 // EC2 and OKP curve
+	coseKeyX     int64 = -2 // EC2 and OKP x coordinate
+	coseKeyY     int64 = -3 // EC2 y coordinate
+	coseKeyRSAN  int64 = -1 // RSA modulus
+	coseKeyRSAE  int64 = -2 // RSA public exponent
+	coseKtyOKP   int64 = 1
+	coseKtyEC2   int64 = 2
+	coseKtyRSA   int64 = 3
+	coseCrvP256  int64 = 1
+	coseCrvEd255 int64 = 6
+)
+
+// minRSAKeyBits is the smallest RSA key accepted for a credential
+const minRSAKeyBits = 2048
+
+// publicKey is a credential public key decoded from its COSE form
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parsePublicKey decodes a COSE_Key of one of the supported algorithms
+func parsePublicKey(coseKey []byte) (*publicKey, error) {
+	decoded, rest, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("unexpected data after the public key")
+	}
+	m, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, errors.New("public key is not a COSE key")
+	}
+
+	kty, _ := m[coseKeyType].(int64)
+	alg, _ := m[coseKeyAlg].(int64)
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		crv, _ := m[coseKeyCrv].(int64)
+		x, _ := m[coseKeyX].([]byte)
+		y, _ := m[coseKeyY].([]byte)
+		if crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 public key")
+		}
+		// crypto/ecdh checks that the point is on the curve
+		point := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid P-256 public key: %w", err)
+		}
+		return &publicKey{alg: alg, key: &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}}, nil
+
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		crv, _ := m[coseKeyCrv].(int64)
+		x, _ := m[coseKeyX].([]byte)
+		if crv != coseCrvEd255 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		return &publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+
+	case kty == coseKtyRSA && alg == AlgRS256:
+		n, _ := m[coseKeyRSAN].([]byte)
+		e, _ := m[coseKeyRSAE].([]byte)
+		exponent := new(big.Int).SetBytes(e)
+		if len(n)*8 < minRSAKeyBits || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA public key")
+		}
+		return &publicKey{alg: alg, key: &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported public key type %d with algorithm %d", kty, alg)
+	}
+}
+
+// verify checks the signature of data made with the private key
+func (k *publicKey) verify(data, signature []byte) error {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, signature) {
+			return errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("invalid signature")
+		}
+	default:
+		return errors.New("unsupported public key")
+	}
+	return nil
+}
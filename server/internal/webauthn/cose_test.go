@@ -0,0 +1,196 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"testing"
+)
+
+// cborHead encodes the initial byte and argument of a CBOR data item
+func cborHead(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+	default:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+	}
+}
+
+func cborInt(v int64) []byte {
+	if v < 0 {
+		return cborHead(1, uint64(-1-v))
+	}
+	return cborHead(0, uint64(v))
+}
+
+func cborBytes(b []byte) []byte {
+	return append(cborHead(2, uint64(len(b))), b...)
+}
+
+// cborIntMap encodes a map with integer keys, the values are already encoded
+func cborIntMap(entries ...any) []byte {
+	out := cborHead(5, uint64(len(entries)/2))
+	for i := 0; i < len(entries); i += 2 {
+		out = append(out, cborInt(entries[i].(int64))...)
+		out = append(out, entries[i+1].([]byte)...)
+	}
+	return out
+}
+
+func ec2Key(x, y []byte) []byte {
+	return cborIntMap(
+		coseKeyType, cborInt(coseKtyEC2),
+		coseKeyAlg, cborInt(AlgES256),
+		coseKeyCrv, cborInt(coseCrvP256),
+		coseKeyX, cborBytes(x),
+		coseKeyY, cborBytes(y),
+	)
+}
+
+func okpKey(x []byte) []byte {
+	return cborIntMap(
+		coseKeyType, cborInt(coseKtyOKP),
+		coseKeyAlg, cborInt(AlgEdDSA),
+		coseKeyCrv, cborInt(coseCrvEd255),
+		coseKeyX, cborBytes(x),
+	)
+}
+
+func rsaKey(n, e []byte) []byte {
+	return cborIntMap(
+		coseKeyType, cborInt(coseKtyRSA),
+		coseKeyAlg, cborInt(AlgRS256),
+		coseKeyRSAN, cborBytes(n),
+		coseKeyRSAE, cborBytes(e),
+	)
+}
+
+func TestParsePublicKey(t *testing.T) {
+	data := []byte("authenticator data and client data hash")
+	digest := sha256.Sum256(data)
+
+	ecPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecSignature, err := ecdsa.SignASN1(rand.Reader, ecPrivate, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecX, ecY := ecPrivate.X.FillBytes(make([]byte, 32)), ecPrivate.Y.FillBytes(make([]byte, 32))
+
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edSignature := ed25519.Sign(edPrivate, data)
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaSignature, err := rsa.SignPKCS1v15(rand.Reader, rsaPrivate, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaN := rsaPrivate.N.Bytes()
+	rsaE := big.NewInt(int64(rsaPrivate.E)).Bytes()
+
+	tests := []struct {
+		name      string
+		key       []byte
+		alg       int64
+		signature []byte
+	}{
+		{"ES256", ec2Key(ecX, ecY), AlgES256, ecSignature},
+		{"EdDSA", okpKey(edPublic), AlgEdDSA, edSignature},
+		{"RS256", rsaKey(rsaN, rsaE), AlgRS256, rsaSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := parsePublicKey(tt.key)
+			if err != nil {
+				t.Fatalf("parsePublicKey() error = %v", err)
+			}
+			if key.alg != tt.alg {
+				t.Errorf("parsePublicKey() alg = %d, want %d", key.alg, tt.alg)
+			}
+			if err := key.verify(data, tt.signature); err != nil {
+				t.Errorf("verify() error = %v", err)
+			}
+			if err := key.verify([]byte("other data"), tt.signature); err == nil {
+				t.Error("verify() succeeded for other data")
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyRejectsInvalidKeys(t *testing.T) {
+	ecPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecX, ecY := ecPrivate.X.FillBytes(make([]byte, 32)), ecPrivate.Y.FillBytes(make([]byte, 32))
+	offCurveY := append([]byte(nil), ecY...)
+	offCurveY[31] ^= 0x01
+
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	largeN := make([]byte, 256)
+	largeN[0] = 0x80
+
+	tests := []struct {
+		name string
+		key  []byte
+	}{
+		{"empty", nil},
+		{"not a map", cborBytes([]byte{0x01})},
+		{"trailing data", append(ec2Key(ecX, ecY), 0x00)},
+		{"P-256 point off the curve", ec2Key(ecX, offCurveY)},
+		{"short P-256 coordinate", ec2Key(ecX[1:], ecY)},
+		{"EC2 key with another curve", cborIntMap(
+			coseKeyType, cborInt(coseKtyEC2),
+			coseKeyAlg, cborInt(AlgES256),
+			coseKeyCrv, cborInt(2), // P-384
+			coseKeyX, cborBytes(ecX),
+			coseKeyY, cborBytes(ecY),
+		)},
+		{"short Ed25519 key", okpKey(make([]byte, ed25519.PublicKeySize-1))},
+		{"RSA key below 2048 bits", rsaKey(smallRSA.N.Bytes(), big.NewInt(int64(smallRSA.E)).Bytes())},
+		{"RSA exponent of 1", rsaKey(largeN, []byte{0x01})},
+		{"RSA exponent too large", rsaKey(largeN, []byte{0x01, 0x00, 0x00, 0x00, 0x01})},
+		{"algorithm not matching the key type", cborIntMap(
+			coseKeyType, cborInt(coseKtyEC2),
+			coseKeyAlg, cborInt(AlgRS256),
+			coseKeyCrv, cborInt(coseCrvP256),
+			coseKeyX, cborBytes(ecX),
+			coseKeyY, cborBytes(ecY),
+		)},
+		{"unsupported algorithm", cborIntMap(
+			coseKeyType, cborInt(coseKtyEC2),
+			coseKeyAlg, cborInt(-35), // ES384
+		)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePublicKey(tt.key); err == nil {
+				t.Error("parsePublicKey() succeeded, want an error")
+			}
+		})
+	}
+}
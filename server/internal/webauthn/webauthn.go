@@ -0,0 +1,365 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config contains the relying party settings of the WebAuthn ceremonies
+type Config struct {
+	RPID    string        // Domain credentials are scoped to, the host of the login page or a parent domain of it
+	RPName  string        // Name authenticators show when a credential is created
+	Origins []string      // Origins the ceremonies may run on, e.g. https://login.example.com
+	Timeout time.Duration // How long the user has to complete a ceremony
+}
+
+// User verification requirements
+const (
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+)
+
+// Client data types of the two ceremonies
+const (
+	clientDataTypeCreate = "webauthn.create"
+	clientDataTypeGet    = "webauthn.get"
+)
+
+// knownTransports are the authenticator transports kept for a credential, browsers use them to
+// decide how to reach the authenticator
+var knownTransports = []string{"usb", "nfc", "ble", "smart-card", "hybrid", "internal"}
+
+// Options and responses use the JSON serialization of WebAuthn Level 3, which browsers produce
+// with PublicKeyCredential.toJSON() and parse with PublicKeyCredential.parseCreationOptionsFromJSON()
+// and parseRequestOptionsFromJSON(). Binary values are unpadded base64url.
+
+type RelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type CredentialParameters struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions are passed to navigator.credentials.create() to register a credential
+type CreationOptions struct {
+	RP                     RelyingParty           `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	Challenge              string                 `json:"challenge"`
+	PubKeyCredParams       []CredentialParameters `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions are passed to navigator.credentials.get() to authenticate with a credential
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// RegistrationResponse is the credential navigator.credentials.create() returned
+type RegistrationResponse struct {
+	ID       string `json:"id" validate:"required,max=2048"`
+	RawID    string `json:"rawId" validate:"required,max=2048"`
+	Type     string `json:"type" validate:"required,eq=public-key"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON" validate:"required,max=8192"`
+		AttestationObject string   `json:"attestationObject" validate:"required,max=65536"`
+		Transports        []string `json:"transports" validate:"max=10,dive,max=32"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the credential navigator.credentials.get() returned
+type AssertionResponse struct {
+	ID       string `json:"id" validate:"required,max=2048"`
+	RawID    string `json:"rawId" validate:"required,max=2048"`
+	Type     string `json:"type" validate:"required,eq=public-key"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON" validate:"required,max=8192"`
+		AuthenticatorData string `json:"authenticatorData" validate:"required,max=8192"`
+		Signature         string `json:"signature" validate:"required,max=2048"`
+		UserHandle        string `json:"userHandle" validate:"max=128"`
+	} `json:"response"`
+}
+
+// Credential is a newly registered credential
+type Credential struct {
+	ID           []byte
+	PublicKey    []byte // COSE_Key
+	SignCount    uint32
+	AAGUID       []byte // Model of the authenticator, all zeros when not disclosed
+	Transports   []string
+	UserVerified bool
+}
+
+// Assertion is the result of a successful authentication with a credential
+type Assertion struct {
+	SignCount    uint32
+	UserVerified bool
+}
+
+// clientData is the data the browser collects for a ceremony, signed along with the
+// authenticator data
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// NewChallenge generates a random challenge for a ceremony
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating challenge: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// EncodeID encodes a credential ID or user handle the way browsers expect it
+func EncodeID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// DecodeID decodes a credential ID or user handle, padding is tolerated
+func DecodeID(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// CreationOptions builds the options for registering a credential. Credentials the user
+// already has are excluded so that an authenticator is not registered twice.
+func (cfg Config) CreationOptions(challenge string, user UserEntity, exclude []CredentialDescriptor) CreationOptions {
+	if exclude == nil {
+		exclude = []CredentialDescriptor{}
+	}
+	return CreationOptions{
+		RP:        RelyingParty{ID: cfg.RPID, Name: cfg.RPName},
+		User:      user,
+		Challenge: challenge,
+		PubKeyCredParams: []CredentialParameters{
+			{Type: "public-key", Alg: AlgES256},
+			{Type: "public-key", Alg: AlgEdDSA},
+			{Type: "public-key", Alg: AlgRS256},
+		},
+		Timeout:            cfg.Timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			// Discoverable credentials can also be used as passkeys
+			ResidentKey:      "preferred",
+			UserVerification: UserVerificationPreferred,
+		},
+		// Attestation statements are not verified, so none are asked for
+		Attestation: "none",
+	}
+}
+
+// RequestOptions builds the options for authenticating with a credential. An empty allow list
+// lets the user pick any discoverable credential of the relying party.
+func (cfg Config) RequestOptions(challenge string, allow []CredentialDescriptor, userVerification string) RequestOptions {
+	if allow == nil {
+		allow = []CredentialDescriptor{}
+	}
+	return RequestOptions{
+		Challenge:        challenge,
+		Timeout:          cfg.Timeout.Milliseconds(),
+		RPID:             cfg.RPID,
+		AllowCredentials: allow,
+		UserVerification: userVerification,
+	}
+}
+
+// ChallengeOf returns the challenge the client data of a response answers, used to find the
+// ceremony the response belongs to. The client data is verified later on.
+func ChallengeOf(clientDataJSON string) (string, error) {
+	raw, err := DecodeID(clientDataJSON)
+	if err != nil {
+		return "", fmt.Errorf("invalid client data encoding: %w", err)
+	}
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return "", fmt.Errorf("invalid client data: %w", err)
+	}
+	if data.Challenge == "" {
+		return "", errors.New("client data has no challenge")
+	}
+	return strings.TrimRight(data.Challenge, "="), nil
+}
+
+// VerifyRegistration verifies the response to a registration ceremony started with challenge
+// and returns the new credential. The attestation statement is not verified, the public key is
+// trusted because the authenticated user registers it.
+func (cfg Config) VerifyRegistration(res RegistrationResponse, challenge string) (*Credential, error) {
+	if _, err := cfg.verifyClientData(res.Response.ClientDataJSON, clientDataTypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	attestationObject, err := DecodeID(res.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attestation object encoding: %w", err)
+	}
+	decoded, rest, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attestation object: %w", err)
+	}
+	attestation, ok := decoded.(map[any]any)
+	if !ok || len(rest) != 0 {
+		return nil, errors.New("invalid attestation object")
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("attestation object has no authenticator data")
+	}
+
+	authData, err := cfg.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.credentialID == nil {
+		return nil, errors.New("authenticator data has no credential")
+	}
+
+	// The response must describe the credential the authenticator created
+	rawID, err := DecodeID(res.RawID)
+	if err != nil || !bytes.Equal(rawID, authData.credentialID) {
+		return nil, errors.New("credential ID does not match the authenticator data")
+	}
+	if _, err := parsePublicKey(authData.publicKey); err != nil {
+		return nil, err
+	}
+
+	transports := make([]string, 0, len(res.Response.Transports))
+	for _, transport := range res.Response.Transports {
+		if slices.Contains(knownTransports, transport) && !slices.Contains(transports, transport) {
+			transports = append(transports, transport)
+		}
+	}
+
+	return &Credential{
+		ID:           bytes.Clone(authData.credentialID),
+		PublicKey:    bytes.Clone(authData.publicKey),
+		SignCount:    authData.signCount,
+		AAGUID:       bytes.Clone(authData.aaguid),
+		Transports:   transports,
+		UserVerified: authData.userVerified(),
+	}, nil
+}
+
+// VerifyAssertion verifies the response to an authentication ceremony started with challenge
+// against the stored public key and signature counter of the credential. A counter that did not
+// increase means the authenticator was cloned, unless the authenticator does not count at all.
+func (cfg Config) VerifyAssertion(res AssertionResponse, challenge string, coseKey []byte, signCount uint32, requireUserVerification bool) (*Assertion, error) {
+	rawClientData, err := cfg.verifyClientData(res.Response.ClientDataJSON, clientDataTypeGet, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	rawAuthData, err := DecodeID(res.Response.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authenticator data encoding: %w", err)
+	}
+	authData, err := cfg.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if requireUserVerification && !authData.userVerified() {
+		return nil, errors.New("user was not verified")
+	}
+
+	signature, err := DecodeID(res.Response.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	key, err := parsePublicKey(coseKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(rawClientData)
+	if err := key.verify(append(bytes.Clone(rawAuthData), clientDataHash[:]...), signature); err != nil {
+		return nil, err
+	}
+
+	if (authData.signCount != 0 || signCount != 0) && authData.signCount <= signCount {
+		return nil, errors.New("signature counter did not increase, the authenticator may have been cloned")
+	}
+
+	return &Assertion{
+		SignCount:    authData.signCount,
+		UserVerified: authData.userVerified(),
+	}, nil
+}
+
+// verifyClientData checks the client data of a response and returns it decoded from base64url
+func (cfg Config) verifyClientData(encoded, ceremonyType, challenge string) ([]byte, error) {
+	raw, err := DecodeID(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client data encoding: %w", err)
+	}
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid client data: %w", err)
+	}
+
+	if data.Type != ceremonyType {
+		return nil, fmt.Errorf("unexpected client data type %q", data.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimRight(data.Challenge, "=")), []byte(challenge)) != 1 {
+		return nil, errors.New("challenge does not match")
+	}
+	if !slices.Contains(cfg.Origins, data.Origin) {
+		return nil, fmt.Errorf("origin %q is not allowed", data.Origin)
+	}
+	if data.CrossOrigin {
+		return nil, errors.New("cross-origin ceremonies are not allowed")
+	}
+	return raw, nil
+}
+
+// verifyAuthenticatorData checks that the authenticator data is scoped to the relying party and
+// that the user was present
+func (cfg Config) verifyAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	authData, err := parseAuthenticatorData(raw)
+	if err != nil {
+		return nil, err
+	}
+	rpIDHash := sha256.Sum256([]byte(cfg.RPID))
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rpIDHash[:]) != 1 {
+		return nil, errors.New("credential is scoped to another relying party")
+	}
+	if !authData.userPresent() {
+		return nil, errors.New("user was not present")
+	}
+	return authData, nil
+}